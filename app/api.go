@@ -0,0 +1,303 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"miwifi-termui/client"
+)
+
+const replHelp = `Requests:
+  GET /api/xqnetwork/wifi_detail_all [key=value ...] [| .info[0].ssid]
+  POST /api/xqsystem/upnp_switch switch=1
+Commands:
+  history  print entered requests
+  help     print this help
+  exit     leave explorer (Ctrl-D)
+`
+
+// apiCommand calls raw API resource or starts interactive explorer:
+// api [-q selector] [-compact] [METHOD PATH [key=value ...]].
+func (app *Application) apiCommand(args []string) error {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	var (
+		selector = fs.String("q", ".", "jq-style field selector, e.g. .info[0].ssid or .list[].name")
+		compact  = fs.Bool("compact", false, "print compact JSON")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return app.apiREPL()
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: api [-q selector] METHOD PATH [key=value ...]")
+	}
+
+	params, err := parseParams(fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	raw, err := app.client.Call(fs.Arg(0), fs.Arg(1), params)
+	if raw == nil {
+		return err
+	}
+	if printErr := printSelected(os.Stdout, raw, *selector, *compact); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+// apiREPL reads requests line by line, history and line editing are
+// available when stdin is a terminal.
+func (app *Application) apiREPL() error {
+	var (
+		readLine func() (string, error)
+		out      io.Writer = os.Stdout
+	)
+
+	if fd := int(syscall.Stdin); terminal.IsTerminal(fd) {
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(fd, state)
+
+		term := terminal.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "miwifi> ")
+		if w, h, err := terminal.GetSize(fd); err == nil {
+			term.SetSize(w, h)
+		}
+		readLine = term.ReadLine
+		out = term
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		readLine = func() (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
+
+	fmt.Fprint(out, "API explorer, type help for usage\n")
+
+	var history []string
+	for {
+		line, err := readLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Fprint(out, replHelp)
+			continue
+		case "history":
+			for i, h := range history {
+				fmt.Fprintf(out, "%4d  %s\n", i+1, h)
+			}
+			continue
+		}
+
+		history = append(history, line)
+		if err := app.replCall(out, line); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
+
+// replCall makes request described by REPL line and prints its result.
+func (app *Application) replCall(out io.Writer, line string) error {
+	// selector follows the last " | ", so params values may contain pipes
+	selector := "."
+	if i := strings.LastIndex(line, " | "); i >= 0 {
+		selector = strings.TrimSpace(line[i+3:])
+		line = line[:i]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return errors.New("expected METHOD PATH [key=value ...]")
+	}
+	params, err := parseParams(fields[2:])
+	if err != nil {
+		return err
+	}
+
+	raw, err := app.client.Call(fields[0], fields[1], params)
+	if raw == nil {
+		return err
+	}
+	if printErr := printSelected(out, raw, selector, false); printErr != nil {
+		return printErr
+	}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return nil // response with error code is already printed
+	}
+	return err
+}
+
+// parseParams parses request params from key=value arguments.
+func parseParams(args []string) (url.Values, error) {
+	params := url.Values{}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid param %q, expected key=value", arg)
+		}
+		params.Add(kv[0], kv[1])
+	}
+	return params, nil
+}
+
+// printSelected prints JSON values selected from raw response.
+func printSelected(out io.Writer, raw json.RawMessage, selector string, compact bool) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("unmarshaling error: %w", err)
+	}
+
+	values, err := selectJSON(doc, selector)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		var data []byte
+		if compact {
+			data, err = json.Marshal(v)
+		} else {
+			data, err = json.MarshalIndent(v, "", "  ")
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\n", data)
+	}
+	return nil
+}
+
+// selectJSON selects values from decoded JSON document by jq-style
+// selector which supports field access (.a.b), array index ([0]) and
+// array iteration ([]), e.g. ".list[].name".
+func selectJSON(doc interface{}, selector string) ([]interface{}, error) {
+	if !strings.HasPrefix(selector, ".") {
+		return nil, fmt.Errorf("selector must start with '.': %q", selector)
+	}
+
+	values := []interface{}{doc}
+	rest := selector[1:]
+
+	for rest != "" {
+		var next []interface{}
+
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in selector: %q", selector)
+			}
+			index := rest[1:end]
+			rest = rest[end+1:]
+
+			for _, v := range values {
+				arr, ok := v.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("can't index %s with [%s]", jsonType(v), index)
+				}
+				if index == "" {
+					next = append(next, arr...)
+					continue
+				}
+				i, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, fmt.Errorf("invalid array index: %q", index)
+				}
+				if i < 0 {
+					i += len(arr)
+				}
+				if i < 0 || i >= len(arr) {
+					next = append(next, nil)
+					continue
+				}
+				next = append(next, arr[i])
+			}
+		default:
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				return nil, fmt.Errorf("empty field name in selector: %q", selector)
+			}
+
+			for _, v := range values {
+				switch obj := v.(type) {
+				case map[string]interface{}:
+					next = append(next, obj[key])
+				case nil:
+					next = append(next, nil)
+				default:
+					return nil, fmt.Errorf("can't get field %q of %s", key, jsonType(v))
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
@@ -13,23 +13,36 @@ import (
 	"miwifi-termui/ui"
 )
 
+// firmwareCheckInterval is an interval of firmware update checks,
+// it doesn't follow fetch data interval to not hammer update servers.
+const firmwareCheckInterval = time.Hour
+
 // New creates and returns new application
-func New(mac, host, username, password string, interval time.Duration, logger *log.Logger) *Application {
+func New(mac, host string, opts client.TransportOptions, username, password string, interval time.Duration, adaptive bool, logger *log.Logger) (*Application, error) {
 	var app Application
 
+	transport, err := client.NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = 3
 	retryClient.Logger = logger
+	retryClient.HTTPClient.Transport = transport
 
-	app.client = client.New(mac, host, retryClient.StandardClient())
+	app.client, err = client.New(mac, host, retryClient.StandardClient())
+	if err != nil {
+		return nil, err
+	}
 	app.logger = logger
 
 	app.username = username
 	app.password = password
 
-	app.interval = interval
+	app.interval = newPollInterval(interval, adaptive)
 
-	return &app
+	return &app, nil
 }
 
 type Application struct {
@@ -37,7 +50,7 @@ type Application struct {
 	logger   *log.Logger
 	username string
 	password string
-	interval time.Duration
+	interval *pollInterval
 }
 
 func (app *Application) Run(ctlName string) (code int) {
@@ -58,6 +71,8 @@ func (app *Application) Run(ctlName string) (code int) {
 		return 1
 	}
 
+	caps := app.capabilitiesOrAll()
+
 	if err := termui.Init(); err != nil {
 		app.logger.Error(fmt.Sprintf("failed to initialize termui: %v", err))
 		return 1
@@ -66,23 +81,11 @@ func (app *Application) Run(ctlName string) (code int) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	var controller ui.Controller
-
-	switch ctlName {
-	case "dash":
-		controller = ui.NewDashboard(app.startPollingStat(ctx, app.interval), app.startPollingBand(ctx, app.interval))
-	case "net":
-		controller = ui.NewNETController(app.startPollingStat(ctx, app.interval), app.startPollingBand(ctx, app.interval))
-	case "cpu":
-		controller = ui.NewCPUController(app.startPollingStat(ctx, app.interval))
-	case "dev":
-		controller = ui.NewDevController(app.startPollingStat(ctx, app.interval))
-	case "info":
-		controller = ui.NewInfoController(app.startPollingStat(ctx, app.interval))
-	case "mem":
-		controller = ui.NewMEMController(app.startPollingStat(ctx, app.interval))
-	default:
-		app.logger.Fatal(fmt.Sprintf("Invalid ui controller name: %s", ctlName))
+	controller, err := app.controller(ctx, ctlName, caps)
+	if err != nil {
+		termui.Close()
+		fmt.Println(err)
+		return 1
 	}
 
 	app.logger.Debug("Init UI controller: " + ctlName)
@@ -90,7 +93,10 @@ func (app *Application) Run(ctlName string) (code int) {
 	controller.Init(ctx)
 	controller.Resize()
 
-	termui.Render(controller)
+	indicator := ui.NewIntervalIndicator(app.interval)
+	indicator.Resize()
+
+	termui.Render(controller, indicator)
 
 	ev := termui.PollEvents()
 	tick := time.Tick(time.Second)
@@ -99,14 +105,26 @@ Loop:
 	for {
 		select {
 		case e := <-ev:
+			if h, ok := controller.(ui.Handler); ok && h.Handle(e) {
+				termui.Render(controller, indicator)
+				continue
+			}
 			switch {
 			case e.Type == termui.KeyboardEvent && e.ID == "q":
 				break Loop
+			case e.Type == termui.KeyboardEvent && (e.ID == "+" || e.ID == "="):
+				app.interval.Step(1)
+			case e.Type == termui.KeyboardEvent && e.ID == "-":
+				app.interval.Step(-1)
+			case e.Type == termui.KeyboardEvent && e.ID == "*":
+				app.interval.ToggleAdaptive()
 			case e.Type == termui.ResizeEvent:
 				controller.Resize()
+				indicator.Resize()
 			}
+			termui.Render(controller, indicator)
 		case <-tick:
-			termui.Render(controller)
+			termui.Render(controller, indicator)
 		}
 	}
 
@@ -122,82 +140,294 @@ Loop:
 	return 0
 }
 
-func (app *Application) startPollingStat(ctx context.Context, interval time.Duration) ui.StreamStatRead {
-	stream := make(chan client.Stat, 1)
+// controller creates UI controller by name, streams and actions which
+// router doesn't support are left nil, so controllers hide them.
+func (app *Application) controller(ctx context.Context, name string, caps client.Capabilities) (ui.Controller, error) {
+	if supported, ok := controllerCapabilities[name]; ok && !supported(caps) {
+		return nil, fmt.Errorf("%s view is not supported by %s", name, caps.Key())
+	}
 
-	go func() {
+	var (
+		limiter      ui.QoSLimiter
+		parental     ui.ParentalControl
+		clock        ui.SystemClock
+		switcher     ui.VPNSwitcher
+		configurator ui.IPv6Configurator
+	)
+	if caps.QoS {
+		limiter = app.client
+	}
+	if caps.Parental {
+		parental = app.client
+	}
+	if caps.Time {
+		clock = app.client
+	}
+	if caps.VPN {
+		switcher = app.client
+	}
+	if caps.IPv6 {
+		configurator = app.client
+	}
 
-		defer func() {
-			close(stream)
+	pollDevices := func() ui.StreamDevicesRead {
+		if !caps.Devices {
+			return nil
+		}
+		return app.startPollingDevices(ctx, app.interval)
+	}
+	pollQoS := func() ui.StreamQoSRead {
+		if !caps.QoS {
+			return nil
+		}
+		return app.startPollingQoS(ctx, app.interval)
+	}
+	pollWAN := func() ui.StreamWANRead {
+		if !caps.WAN {
+			return nil
+		}
+		return app.startPollingWAN(ctx, app.interval)
+	}
+	pollROM := func() ui.StreamROMRead {
+		if !caps.Firmware {
+			return nil
+		}
+		return app.startPollingROM(ctx, newPollInterval(firmwareCheckInterval, false))
+	}
+	pollTime := func() ui.StreamTimeRead {
+		if !caps.Time {
+			return nil
+		}
+		return app.startPollingTime(ctx, app.interval)
+	}
+	pollVPN := func() ui.StreamVPNRead {
+		if !caps.VPN {
+			return nil
+		}
+		return app.startPollingVPN(ctx, app.interval)
+	}
+	pollIPv6 := func() ui.StreamIPv6Read {
+		if !caps.IPv6 {
+			return nil
+		}
+		return app.startPollingIPv6(ctx, app.interval)
+	}
 
-			if err := recover(); err != nil {
-				app.logger.Error(fmt.Sprintf("panic recover: %s", err))
-			}
-		}()
+	switch name {
+	case "dash":
+		return ui.NewDashboard(
+			app.startPollingStat(ctx, app.interval),
+			app.startPollingBand(ctx, app.interval),
+			pollDevices(),
+			pollQoS(),
+			pollWAN(),
+			pollROM(),
+			pollTime(),
+		), nil
+	case "net":
+		return ui.NewNETController(
+			app.startPollingStat(ctx, app.interval),
+			app.startPollingBand(ctx, app.interval),
+			pollWAN(),
+			ui.NETOptions{
+				StreamVPN:  pollVPN(),
+				Switcher:   switcher,
+				StreamIPv6: pollIPv6(),
+			},
+		), nil
+	case "cpu":
+		return ui.NewCPUController(app.startPollingStat(ctx, app.interval)), nil
+	case "dev":
+		return ui.NewDevController(
+			app.startPollingStat(ctx, app.interval),
+			pollDevices(),
+			pollQoS(),
+			limiter,
+			parental,
+		), nil
+	case "info":
+		return ui.NewInfoController(
+			app.startPollingStat(ctx, app.interval),
+			pollWAN(),
+			pollROM(),
+			ui.InfoOptions{
+				StreamTime:   pollTime(),
+				Clock:        clock,
+				StreamIPv6:   pollIPv6(),
+				Configurator: configurator,
+			},
+		), nil
+	case "mem":
+		return ui.NewMEMController(app.startPollingStat(ctx, app.interval)), nil
+	case "dhcp":
+		return ui.NewDHCPController(app.startPollingStat(ctx, app.interval), app.client), nil
+	case "logs":
+		return ui.NewLogsController(app.startPollingLog(ctx, app.interval)), nil
+	case "topo":
+		return ui.NewTopoController(app.startPollingTopo(ctx, app.interval)), nil
+	case "upnp":
+		return ui.NewUPnPController(
+			app.startPollingStat(ctx, app.interval),
+			pollDevices(),
+			app.startPollingUPnP(ctx, app.interval),
+			app.client,
+		), nil
+	case "portfwd":
+		return ui.NewPortFwdController(app.client), nil
+	case "wan":
+		return ui.NewWANConfigController(
+			app.startPollingStat(ctx, app.interval),
+			pollWAN(),
+			app.client,
+		), nil
+	case "lan":
+		return ui.NewLANController(lanManager{app.client, app}), nil
+	default:
+		return nil, fmt.Errorf("invalid ui controller name: %s", name)
+	}
+}
 
+func (app *Application) startPollingStat(ctx context.Context, interval *pollInterval) ui.StreamStatRead {
+	stream := make(chan client.Stat, 1)
+	app.poll(ctx, interval, "Fetching status info", func() (interface{}, error) {
+		start := time.Now()
 		stat, err := app.client.Status()
-		if err != nil {
-			app.logger.Error(err)
-		}
-		stream <- stat
+		interval.Observe(time.Since(start), err)
+		return stat, err
+	}, func(v interface{}) { stream <- v.(client.Stat) }, func() { close(stream) })
+	return stream
+}
 
-		tick := time.Tick(interval)
+func (app *Application) startPollingBand(ctx context.Context, interval *pollInterval) ui.StreamBandRead {
+	stream := make(chan client.Band, 1)
+	app.poll(ctx, interval, "Fetching bandwidth info", func() (interface{}, error) {
+		return app.client.BandwidthTest(true)
+	}, func(v interface{}) { stream <- v.(client.Band) }, func() { close(stream) })
+	return stream
+}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-tick:
-				app.logger.Debug("Fetching status info")
-				result, err := app.client.Status()
-				if err != nil {
-					app.logger.Error(err)
-				} else {
-					stream <- result
-				}
-			}
-		}
-	}()
+func (app *Application) startPollingQoS(ctx context.Context, interval *pollInterval) ui.StreamQoSRead {
+	stream := make(chan client.QoS, 1)
+	app.poll(ctx, interval, "Fetching QoS info", func() (interface{}, error) {
+		return app.client.QoSInfo()
+	}, func(v interface{}) { stream <- v.(client.QoS) }, func() { close(stream) })
+	return stream
+}
 
+func (app *Application) startPollingWAN(ctx context.Context, interval *pollInterval) ui.StreamWANRead {
+	stream := make(chan client.WANInfo, 1)
+	app.poll(ctx, interval, "Fetching WAN info", func() (interface{}, error) {
+		return app.client.WANInfo()
+	}, func(v interface{}) { stream <- v.(client.WANInfo) }, func() { close(stream) })
 	return stream
 }
 
-func (app *Application) startPollingBand(ctx context.Context, interval time.Duration) ui.StreamBandRead {
-	stream := make(chan client.Band, 1)
+func (app *Application) startPollingROM(ctx context.Context, interval *pollInterval) ui.StreamROMRead {
+	stream := make(chan client.ROMUpdate, 1)
+	app.poll(ctx, interval, "Checking firmware update", func() (interface{}, error) {
+		return app.client.CheckROMUpdate()
+	}, func(v interface{}) { stream <- v.(client.ROMUpdate) }, func() { close(stream) })
+	return stream
+}
 
-	go func() {
+func (app *Application) startPollingLog(ctx context.Context, interval *pollInterval) ui.StreamLogRead {
+	stream := make(chan []client.LogEntry, 1)
+	app.poll(ctx, interval, "Fetching system log", func() (interface{}, error) {
+		return app.client.SystemLog()
+	}, func(v interface{}) { stream <- v.([]client.LogEntry) }, func() { close(stream) })
+	return stream
+}
+
+func (app *Application) startPollingTopo(ctx context.Context, interval *pollInterval) ui.StreamTopoRead {
+	stream := make(chan client.TopoNode, 1)
+	app.poll(ctx, interval, "Fetching topology graph", func() (interface{}, error) {
+		return app.client.Topology()
+	}, func(v interface{}) { stream <- v.(client.TopoNode) }, func() { close(stream) })
+	return stream
+}
+
+func (app *Application) startPollingDevices(ctx context.Context, interval *pollInterval) ui.StreamDevicesRead {
+	stream := make(chan []client.DeviceInfo, 1)
+	app.poll(ctx, interval, "Fetching device list", func() (interface{}, error) {
+		return app.client.DeviceList()
+	}, func(v interface{}) { stream <- v.([]client.DeviceInfo) }, func() { close(stream) })
+	return stream
+}
+
+func (app *Application) startPollingUPnP(ctx context.Context, interval *pollInterval) ui.StreamUPnPRead {
+	stream := make(chan client.UPnP, 1)
+	app.poll(ctx, interval, "Fetching UPnP mappings", func() (interface{}, error) {
+		return app.client.UPnP()
+	}, func(v interface{}) { stream <- v.(client.UPnP) }, func() { close(stream) })
+	return stream
+}
 
+func (app *Application) startPollingTime(ctx context.Context, interval *pollInterval) ui.StreamTimeRead {
+	stream := make(chan client.SystemTime, 1)
+	app.poll(ctx, interval, "Fetching system time", func() (interface{}, error) {
+		return app.client.SystemTime()
+	}, func(v interface{}) { stream <- v.(client.SystemTime) }, func() { close(stream) })
+	return stream
+}
+
+func (app *Application) startPollingVPN(ctx context.Context, interval *pollInterval) ui.StreamVPNRead {
+	stream := make(chan client.VPN, 1)
+	app.poll(ctx, interval, "Fetching VPN status", func() (interface{}, error) {
+		return app.client.VPN()
+	}, func(v interface{}) { stream <- v.(client.VPN) }, func() { close(stream) })
+	return stream
+}
+
+func (app *Application) startPollingIPv6(ctx context.Context, interval *pollInterval) ui.StreamIPv6Read {
+	stream := make(chan client.IPv6Info, 1)
+	app.poll(ctx, interval, "Fetching IPv6 info", func() (interface{}, error) {
+		return app.client.IPv6Info()
+	}, func(v interface{}) { stream <- v.(client.IPv6Info) }, func() { close(stream) })
+	return stream
+}
+
+// poll starts goroutine which fetches data right away and then on each
+// interval tick. Fetched data is passed to send, initial data is sent even
+// on error so UI isn't left empty, failed ticks are logged and skipped.
+// Stop is called when polling is finished.
+func (app *Application) poll(
+	ctx context.Context,
+	interval *pollInterval,
+	description string,
+	fetch func() (interface{}, error),
+	send func(interface{}),
+	stop func(),
+) {
+	go func() {
 		defer func() {
-			close(stream)
+			stop()
 
 			if err := recover(); err != nil {
 				app.logger.Error(fmt.Sprintf("panic recover: %s", err))
 			}
 		}()
 
-		band, err := app.client.BandwidthTest(true)
+		result, err := fetch()
 		if err != nil {
 			app.logger.Error(err)
 		}
-		stream <- band
+		send(result)
 
-		tick := time.Tick(interval)
+		tick := ticker(ctx, interval)
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-tick:
-				app.logger.Debug("Fetching bandwidth info")
-				result, err := app.client.BandwidthTest(true)
+				app.logger.Debug(description)
+				result, err := fetch()
 				if err != nil {
 					app.logger.Error(err)
 				} else {
-					stream <- result
+					send(result)
 				}
 			}
 		}
 	}()
-
-	return stream
 }
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// backupMeta is a configuration backup metadata saved next to the archive.
+type backupMeta struct {
+	Platform string    `json:"platform"`
+	Version  string    `json:"version"`
+	Date     time.Time `json:"date"`
+	Size     int       `json:"size"`
+	SHA256   string    `json:"sha256"`
+}
+
+func metaPath(archivePath string) string {
+	return archivePath + ".json"
+}
+
+// backupCommand downloads router configuration backup: backup [-o file].
+func (app *Application) backupCommand(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := fs.String("o", "", "archive file path, defaults to miwifi-<platform>-<version>-<date>.des")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stat, err := app.client.Status()
+	if err != nil {
+		return err
+	}
+
+	archiveURL, err := app.client.CreateBackup()
+	if err != nil {
+		return err
+	}
+
+	var archive bytes.Buffer
+	if err := app.client.DownloadBackup(archiveURL, &archive); err != nil {
+		return err
+	}
+	if archive.Len() == 0 {
+		return errors.New("router returned empty backup archive")
+	}
+
+	sum := sha256.Sum256(archive.Bytes())
+	meta := backupMeta{
+		Platform: stat.Hardware.Platform,
+		Version:  stat.Hardware.Version,
+		Date:     time.Now(),
+		Size:     archive.Len(),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("miwifi-%s-%s-%s.des", meta.Platform, meta.Version, meta.Date.Format("20060102-150405"))
+	}
+
+	if err := ioutil.WriteFile(path, archive.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(metaPath(path), data, 0600); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	fmt.Printf("Backup of %s %s is saved to %s\n", meta.Platform, meta.Version, path)
+	return nil
+}
+
+// restoreCommand restores router configuration from backup: restore [-force] file.
+func (app *Application) restoreCommand(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	force := fs.Bool("force", false, "restore backup made on another router platform")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: restore [-force] file")
+	}
+	path := fs.Arg(0)
+
+	archive, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	data, err := ioutil.ReadFile(metaPath(path))
+	if err != nil {
+		return fmt.Errorf("failed to read metadata: %w", err)
+	}
+	var meta backupMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	sum := sha256.Sum256(archive)
+	if len(archive) != meta.Size || hex.EncodeToString(sum[:]) != meta.SHA256 {
+		return errors.New("archive integrity check failed: checksum mismatch")
+	}
+
+	stat, err := app.client.Status()
+	if err != nil {
+		return err
+	}
+	if stat.Hardware.Platform != meta.Platform && !*force {
+		return fmt.Errorf("backup is made on %s, router is %s; use -force to restore anyway",
+			meta.Platform, stat.Hardware.Platform)
+	}
+	if stat.Hardware.Version != meta.Version {
+		fmt.Fprintf(os.Stderr, "Warning: backup is made on firmware %s, router runs %s\n",
+			meta.Version, stat.Hardware.Version)
+	}
+
+	if err := app.client.RestoreBackup(bytes.NewReader(archive)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Configuration from %s (%s) is restored, router is rebooting\n",
+		path, meta.Date.Format(time.RFC1123))
+	return nil
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"miwifi-termui/client"
+)
+
+// capabilitiesFile is a probed capabilities cache file name in the user
+// cache directory.
+const capabilitiesFile = "miwifi-termui/capabilities.json"
+
+// capability reports whether router has the capability.
+type capability func(caps client.Capabilities) bool
+
+// controllerCapabilities are capabilities required by UI controllers,
+// controllers which are not listed are always available.
+var controllerCapabilities = map[string]capability{
+	"portfwd": func(caps client.Capabilities) bool { return caps.PortForward },
+	"dhcp":    func(caps client.Capabilities) bool { return caps.DHCP },
+	"logs":    func(caps client.Capabilities) bool { return caps.SystemLog },
+	"topo":    func(caps client.Capabilities) bool { return caps.Topology },
+	"upnp":    func(caps client.Capabilities) bool { return caps.UPnP },
+	"lan":     func(caps client.Capabilities) bool { return caps.LAN },
+	"wan":     func(caps client.Capabilities) bool { return caps.WAN },
+}
+
+// commandCapabilities are capabilities required by CLI commands,
+// commands which are not listed are always available.
+var commandCapabilities = map[string]capability{
+	"portfwd":  func(caps client.Capabilities) bool { return caps.PortForward },
+	"firmware": func(caps client.Capabilities) bool { return caps.Firmware },
+	"logs":     func(caps client.Capabilities) bool { return caps.SystemLog },
+	"backup":   func(caps client.Capabilities) bool { return caps.Backup },
+	"restore":  func(caps client.Capabilities) bool { return caps.Backup },
+	"system":   func(caps client.Capabilities) bool { return caps.Time },
+}
+
+// capabilitiesCommand prints router capabilities: capabilities [-refresh].
+func (app *Application) capabilitiesCommand(args []string) error {
+	fs := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+	refresh := fs.Bool("refresh", false, "probe router again instead of using cache")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	caps, err := app.capabilities(*refresh)
+	if err != nil {
+		return err
+	}
+	return printJSON(caps)
+}
+
+// capabilities returns router capabilities from the cache, router is
+// probed when it's model or firmware version is not cached yet.
+func (app *Application) capabilities(refresh bool) (client.Capabilities, error) {
+	stat, err := app.client.Status()
+	if err != nil {
+		return client.Capabilities{}, err
+	}
+	key := client.Capabilities{Platform: stat.Hardware.Platform, Version: stat.Hardware.Version}.Key()
+
+	cache, err := loadCapabilities()
+	if err != nil {
+		app.logger.Warn(fmt.Sprintf("can't load capabilities cache: %v", err))
+	}
+	if caps, ok := cache[key]; ok && !refresh {
+		return caps, nil
+	}
+
+	app.logger.Debug("Probing capabilities of " + key)
+	caps, err := app.client.ProbeCapabilities()
+	if err != nil {
+		return caps, err
+	}
+
+	if cache == nil {
+		cache = make(map[string]client.Capabilities)
+	}
+	cache[key] = caps
+	if err := saveCapabilities(cache); err != nil {
+		app.logger.Warn(fmt.Sprintf("can't save capabilities cache: %v", err))
+	}
+
+	return caps, nil
+}
+
+// capabilitiesOrAll returns router capabilities, everything is treated as
+// available when capabilities can't be probed to not hide working features.
+func (app *Application) capabilitiesOrAll() client.Capabilities {
+	caps, err := app.capabilities(false)
+	if err != nil {
+		app.logger.Error(fmt.Sprintf("capabilities probing failed: %v", err))
+		return client.Capabilities{
+			QoS:         true,
+			PortForward: true,
+			DHCP:        true,
+			WAN:         true,
+			LAN:         true,
+			IPv6:        true,
+			VPN:         true,
+			UPnP:        true,
+			Devices:     true,
+			Topology:    true,
+			Parental:    true,
+			Firmware:    true,
+			SystemLog:   true,
+			Time:        true,
+			Backup:      true,
+		}
+	}
+	return caps
+}
+
+func capabilitiesPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, capabilitiesFile), nil
+}
+
+// loadCapabilities reads capabilities cache keyed by Capabilities.Key.
+func loadCapabilities() (map[string]client.Capabilities, error) {
+	path, err := capabilitiesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cache map[string]client.Capabilities
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// saveCapabilities writes capabilities cache.
+func saveCapabilities(cache map[string]client.Capabilities) error {
+	path, err := capabilitiesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// command is a CLI command handler which gets command arguments.
+type command func(app *Application, args []string) error
+
+var commands = map[string]command{
+	"portfwd":      (*Application).portFwdCommand,
+	"firmware":     (*Application).firmwareCommand,
+	"logs":         (*Application).logsCommand,
+	"backup":       (*Application).backupCommand,
+	"restore":      (*Application).restoreCommand,
+	"system":       (*Application).systemCommand,
+	"passwd":       (*Application).passwdCommand,
+	"capabilities": (*Application).capabilitiesCommand,
+	"api":          (*Application).apiCommand,
+}
+
+// exitCode is a command result which sets exit code without error message.
+type exitCode int
+
+func (e exitCode) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
+
+// Exec runs CLI command with arguments, args[0] is a command name.
+func (app *Application) Exec(args []string) (code int) {
+
+	defer func() {
+		if err := recover(); err != nil {
+			app.logger.Error(fmt.Sprintf("panic recover: %s", err))
+			code = 1
+		}
+	}()
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+		return 2
+	}
+
+	app.logger.Debug("Running command: " + args[0])
+
+	if err := app.client.Login(app.username, app.password); err != nil {
+		fmt.Fprintln(os.Stderr, "Connection error")
+		app.logger.Error(err)
+		return 1
+	}
+
+	defer func() {
+		if err := app.client.Logout(); err != nil {
+			app.logger.Error(err)
+		}
+	}()
+
+	if supported, ok := commandCapabilities[args[0]]; ok {
+		if caps := app.capabilitiesOrAll(); !supported(caps) {
+			fmt.Fprintf(os.Stderr, "Command %s is not supported by %s\n", args[0], caps.Key())
+			return 1
+		}
+	}
+
+	if err := cmd(app, args[1:]); err != nil {
+		var exit exitCode
+		if errors.As(err, &exit) {
+			return int(exit)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		app.logger.Error(err)
+		return 1
+	}
+
+	return 0
+}
+
+// printJSON writes value to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
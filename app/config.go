@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"miwifi-termui/client"
+)
+
+// configFile is a config file name in the user config directory.
+const configFile = "miwifi-termui/config.json"
+
+// Config is an application config persisted between runs.
+type Config struct {
+	// Host is a last used router host.
+	Host string `json:"host,omitempty"`
+	// Profiles are named router connection settings.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Profile is a router connection settings, password and proxy credentials
+// are never stored.
+type Profile struct {
+	Host      string                  `json:"host"`
+	Username  string                  `json:"username,omitempty"`
+	Transport client.TransportOptions `json:"transport"`
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFile), nil
+}
+
+// LoadConfig reads application config, empty config is returned when
+// there is no config file yet.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// SaveConfig writes application config.
+func SaveConfig(cfg Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	profiles := make(map[string]Profile, len(cfg.Profiles))
+	for name, profile := range cfg.Profiles {
+		profile.Transport.Proxy = stripCredentials(profile.Transport.Proxy)
+		profiles[name] = profile
+	}
+	cfg.Profiles = profiles
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// stripCredentials removes user and password from the proxy URL.
+func stripCredentials(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"miwifi-termui/client"
+)
+
+// discoveryTimeout is a timeout of single host probe during discovery.
+const discoveryTimeout = 3 * time.Second
+
+// DiscoverHost looks for the router on the local network: the remembered
+// host, the default gateway and the well-known hostname are probed, user
+// picks one when several routers respond. Choice is remembered in config,
+// empty host is returned when nothing is found. Progress is printed to
+// stderr to keep stdout clean for command output.
+func DiscoverHost(reader *bufio.Reader) string {
+	// broken config isn't overwritten, only the host isn't remembered
+	cfg, cfgErr := LoadConfig()
+	if cfgErr != nil {
+		fmt.Fprintf(os.Stderr, "can't load config, router host won't be remembered: %v\n", cfgErr)
+	}
+
+	hosts := client.DiscoveryCandidates()
+	if cfg.Host != "" {
+		hosts = append([]string{cfg.Host}, hosts...)
+	}
+
+	fmt.Fprintln(os.Stderr, "Discovering router...")
+	routers := client.Discover(&http.Client{Timeout: discoveryTimeout}, dedupHosts(hosts))
+
+	var host string
+	switch {
+	case len(routers) == 0:
+		fmt.Fprintln(os.Stderr, "Router not found")
+		return ""
+	case routers[0] == cfg.Host, len(routers) == 1:
+		host = routers[0]
+	default:
+		host = pickHost(reader, routers)
+	}
+	fmt.Fprintln(os.Stderr, "Using router at "+host)
+
+	if cfgErr == nil && host != cfg.Host {
+		cfg.Host = host
+		if err := SaveConfig(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "can't remember router host: %v\n", err)
+		}
+	}
+	return host
+}
+
+// pickHost asks user to choose one of the routers, the first one is used by
+// default.
+func pickHost(reader *bufio.Reader, routers []string) string {
+	fmt.Fprintln(os.Stderr, "Several routers found:")
+	for i, router := range routers {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, router)
+	}
+
+	for {
+		fmt.Fprintf(os.Stderr, "Choose router [1-%d] (1): ", len(routers))
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" || err != nil {
+			return routers[0]
+		}
+
+		n, err := strconv.Atoi(line)
+		if err == nil && n >= 1 && n <= len(routers) {
+			return routers[n-1]
+		}
+	}
+}
+
+func dedupHosts(hosts []string) []string {
+	seen := make(map[string]bool, len(hosts))
+	unique := hosts[:0]
+	for _, host := range hosts {
+		if !seen[host] {
+			seen[host] = true
+			unique = append(unique, host)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"miwifi-termui/humanize"
+)
+
+// updateAvailableCode is an exit code of firmware check when update is available.
+const updateAvailableCode exitCode = 10
+
+// firmwareCommand checks firmware updates: firmware check|status.
+func (app *Application) firmwareCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: firmware check|status")
+	}
+
+	switch args[0] {
+	case "check":
+		return app.firmwareCheck()
+	case "status":
+		return app.firmwareStatus()
+	default:
+		return fmt.Errorf("unknown firmware command: %s", args[0])
+	}
+}
+
+func (app *Application) firmwareCheck() error {
+	stat, err := app.client.Status()
+	if err != nil {
+		return err
+	}
+
+	update, err := app.client.CheckROMUpdate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Platform: %s\n", stat.Hardware.Platform)
+	fmt.Printf("Current version: %s (%s)\n", stat.Hardware.Version, stat.Hardware.Channel)
+
+	if !update.Available() {
+		fmt.Println("Firmware is up to date")
+		return nil
+	}
+
+	fmt.Printf("Available version: %s (%s)\n", update.Version, humanize.Bytes(update.Size))
+	if update.ChangeLog != "" {
+		fmt.Printf("Changelog:\n%s\n", update.ChangeLog)
+	}
+
+	return updateAvailableCode
+}
+
+func (app *Application) firmwareStatus() error {
+	status, err := app.client.UpgradeStatus()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrade status: %s (%d%%)\n", status, status.Percent)
+	return nil
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// slowStatus is a status request duration treated as slow router response
+// in adaptive polling mode.
+const slowStatus = 2 * time.Second
+
+// intervalSteps are polling intervals switched by hotkeys.
+var intervalSteps = []time.Duration{
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+}
+
+// pollInterval is a polling interval which may change while pollers are
+// running: it's set by user and in adaptive mode backs off while router
+// is slow or failing.
+type pollInterval struct {
+	mu       sync.Mutex
+	base     time.Duration
+	current  time.Duration
+	adaptive bool
+	changed  chan struct{}
+}
+
+func newPollInterval(interval time.Duration, adaptive bool) *pollInterval {
+	return &pollInterval{
+		base:     interval,
+		current:  interval,
+		adaptive: adaptive,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Get returns effective polling interval.
+func (p *pollInterval) Get() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// Changed returns channel which is closed on the next interval change.
+func (p *pollInterval) Changed() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.changed
+}
+
+// Step switches base interval to the next longer (dir > 0) or shorter
+// (dir < 0) step, adaptive backoff is reset.
+func (p *pollInterval) Step(dir int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := 0
+	for i < len(intervalSteps)-1 && intervalSteps[i] < p.base {
+		i++
+	}
+	switch {
+	case dir > 0 && intervalSteps[i] > p.base:
+	case dir > 0 && i < len(intervalSteps)-1:
+		i++
+	case dir < 0 && intervalSteps[i] < p.base:
+	case dir < 0 && i > 0:
+		i--
+	}
+
+	p.base = intervalSteps[i]
+	p.set(p.base)
+}
+
+// ToggleAdaptive switches adaptive mode, effective interval is reset to
+// the base one.
+func (p *pollInterval) ToggleAdaptive() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.adaptive = !p.adaptive
+	p.set(p.base)
+}
+
+// Observe adjusts interval in adaptive mode by status request result: it's
+// doubled while router is slow or failing and halved back to the base one
+// when router is healthy.
+func (p *pollInterval) Observe(duration time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.adaptive {
+		return
+	}
+
+	current := p.current
+	if err != nil || duration > slowStatus {
+		current *= 2
+		// base interval set by flag may be longer than the longest step
+		max := intervalSteps[len(intervalSteps)-1]
+		if p.base > max {
+			max = p.base
+		}
+		if current > max {
+			current = max
+		}
+	} else {
+		current /= 2
+		if current < p.base {
+			current = p.base
+		}
+	}
+	p.set(current)
+}
+
+func (p *pollInterval) set(interval time.Duration) {
+	if interval == p.current {
+		return
+	}
+	p.current = interval
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// String returns effective interval and mode.
+func (p *pollInterval) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case !p.adaptive:
+		return p.current.String()
+	case p.current != p.base:
+		return p.current.String() + " adaptive, slowed down"
+	default:
+		return p.current.String() + " adaptive"
+	}
+}
+
+// ticker sends ticks with the polling interval, interval change takes effect
+// without waiting for the pending tick.
+func ticker(ctx context.Context, interval *pollInterval) <-chan time.Time {
+	ticks := make(chan time.Time)
+
+	go func() {
+		for {
+			// change channel is taken before the interval, so a change
+			// between them isn't missed
+			start := time.Now()
+			changed := interval.Changed()
+			timer := time.NewTimer(interval.Get())
+
+		Wait:
+			for {
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-changed:
+					if !timer.Stop() {
+						<-timer.C
+					}
+					changed = interval.Changed()
+					timer.Reset(interval.Get() - time.Since(start))
+				case t := <-timer.C:
+					select {
+					case ticks <- t:
+					case <-ctx.Done():
+						return
+					}
+					break Wait
+				}
+			}
+		}
+	}()
+
+	return ticks
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollInterval_Step(t *testing.T) {
+	for name, tt := range map[string]struct {
+		base time.Duration
+		dir  int
+		want time.Duration
+	}{
+		"longer":             {base: 10 * time.Second, dir: 1, want: 15 * time.Second},
+		"shorter":            {base: 10 * time.Second, dir: -1, want: 5 * time.Second},
+		"longest":            {base: 5 * time.Minute, dir: 1, want: 5 * time.Minute},
+		"shortest":           {base: time.Second, dir: -1, want: time.Second},
+		"above longest":      {base: 10 * time.Minute, dir: 1, want: 5 * time.Minute},
+		"below shortest":     {base: 500 * time.Millisecond, dir: -1, want: time.Second},
+		"non-step longer":    {base: 7 * time.Second, dir: 1, want: 10 * time.Second},
+		"non-step shorter":   {base: 7 * time.Second, dir: -1, want: 5 * time.Second},
+		"non-step below max": {base: 4 * time.Minute, dir: 1, want: 5 * time.Minute},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := newPollInterval(tt.base, false)
+			p.Step(tt.dir)
+			assert.Equal(t, tt.want, p.Get())
+		})
+	}
+}
+
+func TestPollInterval_Observe(t *testing.T) {
+	t.Run("not adaptive", func(t *testing.T) {
+		p := newPollInterval(10*time.Second, false)
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 10*time.Second, p.Get())
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		p := newPollInterval(10*time.Second, true)
+
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 20*time.Second, p.Get())
+		p.Observe(slowStatus+time.Second, nil)
+		assert.Equal(t, 40*time.Second, p.Get())
+		assert.Equal(t, "40s adaptive, slowed down", p.String())
+
+		p.Observe(time.Millisecond, nil)
+		assert.Equal(t, 20*time.Second, p.Get())
+		p.Observe(time.Millisecond, nil)
+		assert.Equal(t, 10*time.Second, p.Get())
+		p.Observe(time.Millisecond, nil)
+		assert.Equal(t, 10*time.Second, p.Get())
+		assert.Equal(t, "10s adaptive", p.String())
+	})
+
+	t.Run("clamped", func(t *testing.T) {
+		p := newPollInterval(2*time.Minute, true)
+
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 4*time.Minute, p.Get())
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 5*time.Minute, p.Get())
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 5*time.Minute, p.Get())
+	})
+
+	t.Run("base above longest step", func(t *testing.T) {
+		p := newPollInterval(10*time.Minute, true)
+
+		p.Observe(0, errors.New("failed"))
+		assert.Equal(t, 10*time.Minute, p.Get())
+		p.Observe(time.Millisecond, nil)
+		assert.Equal(t, 10*time.Minute, p.Get())
+	})
+
+	t.Run("reset by step", func(t *testing.T) {
+		p := newPollInterval(10*time.Second, true)
+
+		p.Observe(0, errors.New("failed"))
+		p.Step(-1)
+		assert.Equal(t, 5*time.Second, p.Get())
+	})
+}
+
+func TestTicker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newPollInterval(time.Minute, false)
+	ticks := ticker(ctx, p)
+
+	// the pending minute tick is rescheduled by the shorter interval
+	for i := 0; i < 6; i++ {
+		p.Step(-1)
+	}
+	assert.Equal(t, time.Second, p.Get())
+
+	select {
+	case <-ticks:
+	case <-time.After(10 * time.Second):
+		t.Fatal("interval change didn't take effect before the pending tick")
+	}
+}
@@ -0,0 +1,18 @@
+package app
+
+import "miwifi-termui/client"
+
+// lanManager is a LAN settings manager which moves the application
+// client to the new router address, so polling continues there.
+type lanManager struct {
+	*client.Client
+	app *Application
+}
+
+// Reconnect logs client in at the router new LAN address.
+func (m lanManager) Reconnect(ip string) error {
+	if err := m.SetHostIP(ip); err != nil {
+		return err
+	}
+	return m.Login(m.app.username, m.app.password)
+}
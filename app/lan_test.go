@@ -0,0 +1,46 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"miwifi-termui/client"
+)
+
+func TestLANManager_Reconnect(t *testing.T) {
+	var nonces []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, "/cgi-bin/luci/api/xqsystem/login", r.URL.Path)
+
+		nonces = append(nonces, r.URL.Query().Get("nonce"))
+		// Router is still restarting network on the first attempt
+		if len(nonces) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"token": "token"}`))
+	}))
+	defer ts.Close()
+
+	c, err := client.New("00:11:22:33:44:55", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := lanManager{Client: c, app: &Application{client: c, username: "admin", password: "secret"}}
+
+	assert.Error(t, m.Reconnect("127.0.0.1"))
+	assert.NoError(t, m.Reconnect("127.0.0.1"))
+
+	// Expected fresh nonce on each attempt
+	if assert.Len(t, nonces, 2) {
+		assert.NotEmpty(t, nonces[0])
+		assert.NotEqual(t, nonces[0], nonces[1])
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"miwifi-termui/client"
+)
+
+// logsCommand prints router system log: logs [-follow].
+func (app *Application) logsCommand(args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := fs.Bool("follow", false, "keep printing new records on fetch data interval")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := app.client.SystemLog()
+	if err != nil {
+		return err
+	}
+	printLog(entries)
+
+	if !*follow {
+		return nil
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	tick := time.NewTicker(app.interval.Get())
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case <-tick.C:
+			app.logger.Debug("Fetching system log")
+			result, err := app.client.SystemLog()
+			if err != nil {
+				app.logger.Error(err)
+				continue
+			}
+			printLog(newLogEntries(entries, result))
+			entries = result
+		}
+	}
+}
+
+func printLog(entries []client.LogEntry) {
+	for _, entry := range entries {
+		if entry.Severity == "" {
+			fmt.Println(entry.Message)
+			continue
+		}
+		fmt.Printf("%s %s.%s %s\n", entry.Time, entry.Facility, entry.Severity, entry.Message)
+	}
+}
+
+// newLogEntries returns records of the current log which follow the last
+// record of the previous one, the whole log is new if there is no such record.
+func newLogEntries(prev, cur []client.LogEntry) []client.LogEntry {
+	if len(prev) == 0 {
+		return cur
+	}
+
+	last := prev[len(prev)-1]
+	for i := len(cur) - 1; i >= 0; i-- {
+		if cur[i] == last {
+			return cur[i+1:]
+		}
+	}
+
+	return cur
+}
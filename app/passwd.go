@@ -0,0 +1,73 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// minPasswordLength is a minimal admin password length accepted by routers.
+const minPasswordLength = 8
+
+// passwdCommand changes router admin password and logs in with the new one.
+func (app *Application) passwdCommand(args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: passwd")
+	}
+
+	password, err := readPassword("New admin password: ")
+	if err != nil {
+		return err
+	}
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", minPasswordLength)
+	}
+	if password == app.password {
+		return errors.New("new password is the same as the current one")
+	}
+
+	confirm, err := readPassword("Repeat new admin password: ")
+	if err != nil {
+		return err
+	}
+	if confirm != password {
+		return errors.New("passwords don't match")
+	}
+
+	if err := app.changePassword(password); err != nil {
+		return err
+	}
+
+	fmt.Println("Admin password is changed")
+	return nil
+}
+
+// changePassword changes router admin password and logs in with the new one.
+func (app *Application) changePassword(password string) error {
+	if err := app.client.ChangePassword(app.password, password); err != nil {
+		return err
+	}
+
+	// passwords are never persisted, so the running application is
+	// the only credential holder to update
+	app.password = password
+	if err := app.client.Login(app.username, app.password); err != nil {
+		return fmt.Errorf("password is changed, but login with it failed: %w", err)
+	}
+	return nil
+}
+
+// readPassword prompts for a password without echo.
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := terminal.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("can't read password: %w", err)
+	}
+	return strings.TrimSpace(string(password)), nil
+}
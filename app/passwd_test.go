@@ -0,0 +1,63 @@
+package app
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"miwifi-termui/client"
+)
+
+func TestApplication_ChangePassword(t *testing.T) {
+	var nonces, passwords []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/cgi-bin/luci/api/xqsystem/login":
+			nonces = append(nonces, r.URL.Query().Get("nonce"))
+			passwords = append(passwords, r.URL.Query().Get("password"))
+			w.WriteHeader(200)
+			w.Write([]byte(`{"token": "token"}`))
+		case "/cgi-bin/luci/;stok=token/api/xqsystem/set_name_password":
+			r.ParseForm()
+			nonces = append(nonces, r.PostForm.Get("nonce"))
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := client.New("00:11:22:33:44:55", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &Application{client: c, username: "admin", password: "old-secret"}
+	if err := c.Login(app.username, app.password); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, app.changePassword("new-secret"))
+	assert.Equal(t, "new-secret", app.password)
+
+	// Expected login, password change and login with the new password
+	if assert.Len(t, nonces, 3) && assert.Len(t, passwords, 2) {
+		assert.NotEqual(t, nonces[0], nonces[2])
+		assert.NotEqual(t, nonces[1], nonces[2])
+		assert.Equal(t, passwordHash("new-secret", nonces[2]), passwords[1])
+	}
+}
+
+// passwordHash returns login password hash the way router expects it.
+func passwordHash(password, nonce string) string {
+	stored := sha1.Sum([]byte(password + "a2ffa5c9be07488bbb04a3a47d3c5f6a"))
+	h := sha1.Sum([]byte(nonce + hex.EncodeToString(stored[:])))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"miwifi-termui/client"
+)
+
+// portFwdCommand manages port forwarding rules: portfwd list|add|rm.
+func (app *Application) portFwdCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: portfwd list|add|rm [flags]")
+	}
+
+	switch args[0] {
+	case "list":
+		return app.portFwdList()
+	case "add":
+		return app.portFwdAdd(args[1:])
+	case "rm":
+		return app.portFwdRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown portfwd command: %s", args[0])
+	}
+}
+
+func (app *Application) portFwdList() error {
+	singles, err := app.client.PortForwards()
+	if err != nil {
+		return err
+	}
+	ranges, err := app.client.PortRangeForwards()
+	if err != nil {
+		return err
+	}
+
+	if singles == nil {
+		singles = []client.PortForward{}
+	}
+	if ranges == nil {
+		ranges = []client.PortRangeForward{}
+	}
+
+	return printJSON(struct {
+		Single []client.PortForward      `json:"single"`
+		Range  []client.PortRangeForward `json:"range"`
+	}{singles, ranges})
+}
+
+func (app *Application) portFwdAdd(args []string) error {
+	fs := flag.NewFlagSet("portfwd add", flag.ContinueOnError)
+	var (
+		name     = fs.String("name", "", "rule name")
+		proto    = fs.String("proto", "tcp", `protocol {"tcp", "udp", "both"}`)
+		ports    = fs.String("port", "", "external port or ports range, e.g. 8080 or 8000-8010")
+		destIP   = fs.String("ip", "", "internal IP address")
+		destPort = fs.Int("dest-port", 0, "internal port, defaults to external port")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" || *ports == "" || *destIP == "" {
+		return errors.New("name, port and ip flags are required")
+	}
+
+	p, err := client.ParseProtocol(*proto)
+	if err != nil {
+		return err
+	}
+	from, to, err := client.ParsePorts(*ports)
+	if err != nil {
+		return err
+	}
+	if *destPort < 0 || *destPort > 65535 {
+		return fmt.Errorf("invalid dest-port: %d", *destPort)
+	}
+
+	if from != to {
+		if *destPort != 0 {
+			return errors.New("dest-port flag can't be used with ports range, range is forwarded to the same ports")
+		}
+		rule := client.PortRangeForward{Name: *name, Proto: p, FromPort: from, ToPort: to, DestIP: *destIP}
+		if err := app.client.AddPortRangeForward(rule); err != nil {
+			return err
+		}
+		return printJSON(rule)
+	}
+
+	if *destPort == 0 {
+		*destPort = from
+	}
+	rule := client.PortForward{Name: *name, Proto: p, Port: from, DestIP: *destIP, DestPort: *destPort}
+	if err := app.client.AddPortForward(rule); err != nil {
+		return err
+	}
+	return printJSON(rule)
+}
+
+func (app *Application) portFwdRemove(args []string) error {
+	fs := flag.NewFlagSet("portfwd rm", flag.ContinueOnError)
+	var (
+		proto = fs.String("proto", "tcp", `protocol {"tcp", "udp", "both"}`)
+		port  = fs.Int("port", 0, "external port or first port of range")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *port == 0 {
+		return errors.New("port flag is required")
+	}
+
+	p, err := client.ParseProtocol(*proto)
+	if err != nil {
+		return err
+	}
+
+	return app.client.DeletePortForward(*port, p)
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// systemCommand reads and changes router clock and name: system show|sync|set.
+func (app *Application) systemCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: system show|sync|set [flags]")
+	}
+
+	switch args[0] {
+	case "show":
+		return app.systemShow()
+	case "sync":
+		return app.systemSync()
+	case "set":
+		return app.systemSet(args[1:])
+	default:
+		return fmt.Errorf("unknown system command: %s", args[0])
+	}
+}
+
+func (app *Application) systemShow() error {
+	name, err := app.client.RouterName()
+	if err != nil {
+		return err
+	}
+	t, err := app.client.SystemTime()
+	if err != nil {
+		return err
+	}
+
+	routerTime, skew := "-", "-"
+	if !t.Time.IsZero() {
+		routerTime = t.Time.Format(time.RFC3339)
+		skew = t.Skew().Truncate(time.Second).String()
+	}
+
+	fmt.Printf("Router name: %s\n", name)
+	fmt.Printf("Router time: %s\n", routerTime)
+	fmt.Printf("Clock skew: %s\n", skew)
+	fmt.Printf("Timezone: %s\n", t.Timezone)
+	fmt.Printf("NTP servers: %s\n", strings.Join(t.NTPServers, ", "))
+	return nil
+}
+
+func (app *Application) systemSync() error {
+	if err := app.client.SetSystemTime(time.Now()); err != nil {
+		return err
+	}
+	fmt.Println("Router clock is synced to local time")
+	return nil
+}
+
+func (app *Application) systemSet(args []string) error {
+	fs := flag.NewFlagSet("system set", flag.ContinueOnError)
+	var (
+		name     = fs.String("name", "", "router name")
+		timezone = fs.String("tz", "", "timezone, e.g. Europe/Berlin")
+		ntp      = fs.String("ntp", "", "comma separated NTP servers")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" && *timezone == "" && *ntp == "" {
+		return errors.New("at least one of name, tz and ntp flags is required")
+	}
+
+	if *name != "" {
+		if err := app.client.SetRouterName(*name); err != nil {
+			return err
+		}
+	}
+	if *timezone != "" {
+		if err := app.client.SetTimezone(*timezone); err != nil {
+			return err
+		}
+	}
+	if *ntp != "" {
+		var servers []string
+		for _, server := range strings.Split(*ntp, ",") {
+			if server = strings.TrimSpace(server); server != "" {
+				servers = append(servers, server)
+			}
+		}
+		if err := app.client.SetNTPServers(servers); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("System settings are updated")
+	return nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// CreateBackup makes router configuration backup and returns archive URL.
+func (c *Client) CreateBackup() (string, error) {
+	params := url.Values{}
+	params.Set("keys", "mi_basic_info,mi_network_info,mi_wifi_info,mi_lan_info,mi_arn_info")
+
+	payload := struct {
+		URL string `json:"url"`
+	}{}
+
+	if err := c.get("/api/misystem/c_backup", params, &payload); err != nil {
+		return "", err
+	}
+
+	if payload.URL == "" {
+		return "", errors.New("empty backup url")
+	}
+
+	return payload.URL, nil
+}
+
+// DownloadBackup writes backup archive by URL returned from CreateBackup to w.
+// Archive path is resolved against the host base path, archive URL pointing
+// to another host is rejected.
+func (c *Client) DownloadBackup(archiveURL string, w io.Writer) error {
+	base, err := url.Parse(c.baseURL())
+	if err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+	ref, err := url.Parse(archiveURL)
+	if err != nil {
+		return fmt.Errorf("invalid backup url: %w", err)
+	}
+	if ref.Host != "" && !strings.EqualFold(ref.Host, base.Host) {
+		return fmt.Errorf("backup url points to another host: %s", ref.Host)
+	}
+
+	u := *base
+	u.Path = base.Path + path.Clean("/"+ref.Path)
+	u.RawPath = ""
+	u.RawQuery = ref.RawQuery
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("can't build request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("response body error: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreBackup uploads backup archive and restores router configuration from it,
+// router reboots after restore.
+func (c *Client) RestoreBackup(archive io.Reader) error {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("image", "cfg_backup.des")
+	if err != nil {
+		return fmt.Errorf("can't build request: %w", err)
+	}
+	if _, err := io.Copy(part, archive); err != nil {
+		return fmt.Errorf("can't build request: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("can't build request: %w", err)
+	}
+
+	err = c.authorized(func() (*http.Request, error) {
+		url, err := c.buildURL("/api/misystem/c_upload", true)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest("POST", url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, fmt.Errorf("can't build request: %w", err)
+		}
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+
+		return req, nil
+	}, func(req *http.Request) error {
+		return c.call(req, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post("/api/misystem/c_restore", nil, nil)
+}
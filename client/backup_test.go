@@ -0,0 +1,170 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CreateBackup(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/c_backup")
+			// Expected query params
+			assert.NotEmpty(t, r.URL.Query().Get("keys"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"url": "/backup/log/cfg_backup.des", "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		archiveURL, err := c.CreateBackup()
+		assert.NoError(t, err)
+		assert.Equal(t, "/backup/log/cfg_backup.des", archiveURL)
+	})
+
+	t.Run("empty url", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.CreateBackup()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_DownloadBackup(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/backup/log/cfg_backup.des")
+
+			w.WriteHeader(200)
+			w.Write([]byte("archive"))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		var buf bytes.Buffer
+		assert.NoError(t, c.DownloadBackup("/backup/log/cfg_backup.des", &buf))
+		assert.Equal(t, "archive", buf.String())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		err := c.DownloadBackup("/backup/log/cfg_backup.des", ioutil.Discard)
+		assert.Equal(t, &HTTPError{StatusCode: http.StatusNotFound}, err)
+	})
+
+	t.Run("base path", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path under the host base path
+			assert.Equal(t, "/router/backup/log/cfg_backup.des", r.URL.Path)
+
+			w.WriteHeader(200)
+			w.Write([]byte("archive"))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL + "/router",
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		var buf bytes.Buffer
+		assert.NoError(t, c.DownloadBackup(ts.URL+"/backup/log/../log/cfg_backup.des", &buf))
+		assert.Equal(t, "archive", buf.String())
+	})
+
+	t.Run("another host", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request: %s", r.URL)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.Error(t, c.DownloadBackup("http://example.com/backup/log/cfg_backup.des", ioutil.Discard))
+	})
+}
+
+func TestClient_RestoreBackup(t *testing.T) {
+	var paths []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		if r.URL.Path == "/cgi-bin/luci/;stok=token/api/misystem/c_upload" {
+			file, _, err := r.FormFile("image")
+			assert.NoError(t, err)
+			data, _ := ioutil.ReadAll(file)
+			assert.Equal(t, "archive", string(data))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.RestoreBackup(strings.NewReader("archive")))
+	assert.Equal(t, []string{
+		"/cgi-bin/luci/;stok=token/api/misystem/c_upload",
+		"/cgi-bin/luci/;stok=token/api/misystem/c_restore",
+	}, paths)
+}
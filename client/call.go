@@ -0,0 +1,43 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Call makes authorized request to any API resource and returns raw JSON
+// response, GET params are sent in query and POST params are form encoded.
+// Response is returned along with APIError when its code is not zero.
+func (c *Client) Call(method, resource string, params url.Values) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	if method != http.MethodGet && method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method: %s", method)
+	}
+	if !strings.HasPrefix(resource, "/") {
+		resource = "/" + resource
+	}
+
+	var raw json.RawMessage
+
+	err := c.authorized(func() (*http.Request, error) {
+		return c.newRequest(method, resource, params)
+	}, func(req *http.Request) error {
+		raw = nil
+		if err := c.do(req, &raw); err != nil {
+			return err
+		}
+
+		// non object responses are returned as is
+		var apiErr *APIError
+		if err := checkCode(raw); errors.As(err, &apiErr) {
+			return err
+		}
+		return nil
+	})
+
+	return raw, err
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Call(t *testing.T) {
+	t.Run("get", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected method
+			assert.Equal(t, "GET", r.Method)
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/wifi_detail_all")
+			// Expected query params
+			assert.Equal(t, "1", r.URL.Query().Get("band"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"info": [{"ssid": "home"}], "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		raw, err := c.Call("get", "api/xqnetwork/wifi_detail_all", url.Values{"band": {"1"}})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"info": [{"ssid": "home"}], "code": 0}`, string(raw))
+	})
+
+	t.Run("post", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected method
+			assert.Equal(t, "POST", r.Method)
+			// Expected form params
+			assert.Equal(t, "1", r.FormValue("switch"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.Call("POST", "/api/xqsystem/upnp_switch", url.Values{"switch": {"1"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 401, "msg": "invalid token"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		raw, err := c.Call("GET", "/api/xqsystem/unknown", nil)
+		assert.IsType(t, &APIError{}, err)
+		assert.JSONEq(t, `{"code": 401, "msg": "invalid token"}`, string(raw))
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		c := Client{httpClient: http.DefaultClient, token: "token"}
+
+		_, err := c.Call("DELETE", "/api/xqsystem/upnp", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("not authorized", func(t *testing.T) {
+		c := Client{httpClient: http.DefaultClient}
+
+		_, err := c.Call("GET", "/api/xqsystem/upnp", nil)
+		assert.Error(t, err)
+	})
+}
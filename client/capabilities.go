@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Capabilities is a set of API groups available on the router,
+// capabilities depend on the router model and firmware version.
+type Capabilities struct {
+	Platform    string `json:"platform"`
+	Version     string `json:"version"`
+	QoS         bool   `json:"qos"`
+	PortForward bool   `json:"portfwd"`
+	DHCP        bool   `json:"dhcp"`
+	WAN         bool   `json:"wan"`
+	LAN         bool   `json:"lan"`
+	IPv6        bool   `json:"ipv6"`
+	VPN         bool   `json:"vpn"`
+	UPnP        bool   `json:"upnp"`
+	Devices     bool   `json:"devices"`
+	Topology    bool   `json:"topo"`
+	Parental    bool   `json:"parental"`
+	Firmware    bool   `json:"firmware"`
+	SystemLog   bool   `json:"logs"`
+	Time        bool   `json:"time"`
+	Backup      bool   `json:"backup"`
+}
+
+// Key returns cache key of the capabilities, routers of the same
+// platform and firmware version have the same capabilities.
+func (c Capabilities) Key() string {
+	return c.Platform + "/" + c.Version
+}
+
+// ProbeCapabilities checks which API groups are available on the router.
+// Each group is probed by a read only endpoint, group is unavailable when
+// the endpoint is not found, API errors mean the endpoint exists.
+func (c *Client) ProbeCapabilities() (Capabilities, error) {
+	stat, err := c.Status()
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	// backup has no read only endpoint, c_backup creates an archive,
+	// so it isn't probed and is assumed available
+	caps := Capabilities{
+		Platform: stat.Hardware.Platform,
+		Version:  stat.Hardware.Version,
+		Backup:   true,
+	}
+
+	for _, probe := range []struct {
+		resource  string
+		params    url.Values
+		available *bool
+	}{
+		{"/api/misystem/qos_info", nil, &caps.QoS},
+		{"/api/xqnetwork/portforward", url.Values{"ftype": {"1"}}, &caps.PortForward},
+		{"/api/xqnetwork/dhcp_leases", nil, &caps.DHCP},
+		{"/api/xqnetwork/wan_info", nil, &caps.WAN},
+		{"/api/xqnetwork/lan_info", nil, &caps.LAN},
+		{"/api/xqnetwork/ipv6_status", nil, &caps.IPv6},
+		{"/api/xqsystem/vpn_status", nil, &caps.VPN},
+		{"/api/xqsystem/upnp", nil, &caps.UPnP},
+		{"/api/misystem/devicelist", nil, &caps.Devices},
+		{"/api/misystem/topo_graph", nil, &caps.Topology},
+		{"/api/misystem/parentctl_info", nil, &caps.Parental},
+		{"/api/xqsystem/check_rom_update", nil, &caps.Firmware},
+		{"/api/misystem/sys_log", nil, &caps.SystemLog},
+		{"/api/misystem/sys_time", nil, &caps.Time},
+	} {
+		available, err := c.probe(probe.resource, probe.params)
+		if err != nil {
+			return caps, err
+		}
+		*probe.available = available
+	}
+
+	return caps, nil
+}
+
+// probe reports whether the resource is available.
+func (c *Client) probe(resource string, params url.Values) (bool, error) {
+	err := c.get(resource, params, nil)
+
+	var apiErr *APIError
+	var httpErr *HTTPError
+	switch {
+	case err == nil, errors.As(err, &apiErr):
+		return true, nil
+	case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
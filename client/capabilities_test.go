@@ -0,0 +1,90 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ProbeCapabilities(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resource := strings.TrimPrefix(r.URL.Path, "/cgi-bin/luci/;stok=token")
+
+			switch resource {
+			case "/api/misystem/c_backup", "/api/misystem/c_upload":
+				t.Errorf("backup endpoint is probed: %s", resource)
+			case "/api/xqnetwork/portforward":
+				assert.Equal(t, "1", r.URL.Query().Get("ftype"))
+			}
+
+			switch resource {
+			case "/api/misystem/status":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(200)
+				w.Write([]byte(`{"hardware": {"platform": "R3G", "version": "2.28.44"}, "code": 0}`))
+			case "/api/xqsystem/vpn_status", "/api/xqnetwork/ipv6_status", "/api/xqsystem/upgrade_status":
+				w.WriteHeader(http.StatusNotFound)
+			case "/api/misystem/parentctl_info":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(200)
+				w.Write([]byte(`{"code": 1523, "msg": "mac is required"}`))
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(200)
+				w.Write([]byte(`{"code": 0}`))
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		caps, err := c.ProbeCapabilities()
+		assert.NoError(t, err)
+		assert.Equal(t, "R3G/2.28.44", caps.Key())
+		assert.False(t, caps.VPN)
+		assert.False(t, caps.IPv6)
+		assert.True(t, caps.Parental)
+		assert.True(t, caps.QoS)
+		assert.True(t, caps.Firmware)
+		assert.True(t, caps.Backup)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/api/misystem/status") {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(200)
+				w.Write([]byte(`{"code": 0}`))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.ProbeCapabilities()
+		assert.Error(t, err)
+	})
+
+	t.Run("not authorized", func(t *testing.T) {
+		c := Client{httpClient: http.DefaultClient}
+
+		_, err := c.ProbeCapabilities()
+		assert.Error(t, err)
+	})
+}
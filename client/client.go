@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // Stat is a status container entity.
@@ -34,6 +39,13 @@ type DeviceStat struct {
 	Name             string `json:"devname"`
 }
 
+// IsOnline reports whether device is connected now, Online is a number
+// of seconds the device is connected for.
+func (d DeviceStat) IsOnline() bool {
+	seconds, err := strconv.ParseFloat(d.Online, 64)
+	return err == nil && seconds > 0
+}
+
 // MemStat is a device memory status entity.
 type MemStat struct {
 	Usage float64 `json:"usage"`
@@ -66,14 +78,41 @@ type CPUStat struct {
 
 // WANStat is a device WAN status entity.
 type WANStat struct {
-	MaxDownloadSpeed uint64 `json:"maxdownloadspeed,string"`
-	MaxUploadSpeed   uint64 `json:"maxuploadspeed,string"`
-	Upload           uint64 `json:"upload,string"`
-	Download         uint64 `json:"download,string"`
-	UpSpeed          uint64 `json:"upspeed,string"`
-	DownSpeed        uint64 `json:"downspeed,string"`
-	Name             string `json:"devname"`
-	History          string `json:"history"`
+	MaxDownloadSpeed uint64  `json:"maxdownloadspeed,string"`
+	MaxUploadSpeed   uint64  `json:"maxuploadspeed,string"`
+	Upload           uint64  `json:"upload,string"`
+	Download         uint64  `json:"download,string"`
+	UpSpeed          uint64  `json:"upspeed,string"`
+	DownSpeed        uint64  `json:"downspeed,string"`
+	Name             string  `json:"devname"`
+	History          History `json:"history"`
+}
+
+// History is a series of recent WAN downstream speed samples in bytes per
+// second from oldest to newest.
+type History []uint64
+
+// UnmarshalJSON decodes history from comma separated samples string.
+// History is informational only, so invalid samples are skipped and
+// a value of unexpected type is decoded as empty history instead of
+// failing the whole status.
+func (h *History) UnmarshalJSON(data []byte) error {
+	*h = nil
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+
+	for _, sample := range strings.Split(s, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(sample), 10, 64)
+		if err != nil {
+			continue
+		}
+		*h = append(*h, v)
+	}
+
+	return nil
 }
 
 // Band is a bandwidth testing result entity.
@@ -86,28 +125,80 @@ type Band struct {
 	Upload     float64 `json:"upload"`
 }
 
-// New creates and returns new MiWIFI client.
-func New(macAddress, host string, httpClient *http.Client) *Client {
+// New creates and returns new MiWIFI client, host is parsed by ParseHost.
+func New(macAddress, host string, httpClient *http.Client) (*Client, error) {
+	u, err := ParseHost(host)
+	if err != nil {
+		return nil, err
+	}
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 	return &Client{
 		httpClient: httpClient,
-		host:       host,
+		host:       u.String(),
+		mac:        macAddress,
 		nonce:      generateNonce(macAddress),
-	}
+	}, nil
 }
 
-// Client is MiWIFI client.
+// errNoCredentials is returned by relogin when client wasn't logged in by
+// username and password.
+var errNoCredentials = errors.New("no credentials to log in")
+
+// Client is MiWIFI client, it's safe for concurrent use.
 type Client struct {
 	httpClient *http.Client
-	host       string
-	nonce      string
-	token      string
+	mac        string
+	nonce      string // last login nonce, guarded by loginMu
+
+	mu       sync.RWMutex // guards fields below
+	host     string
+	token    string
+	username string
+	password string
+
+	loginMu sync.Mutex // serializes logins
 }
 
-// Login makes client authorization by username and password.
+// Login makes client authorization by username and password, credentials
+// are kept to log in again when the token expires.
 func (c *Client) Login(username, password string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	return c.login(username, password)
+}
+
+// relogin logs in again with kept credentials unless the stale token was
+// already replaced by concurrent login, so requests failed with the same
+// expired token trigger single login.
+func (c *Client) relogin(stale string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	c.mu.RLock()
+	token, username, password := c.token, c.username, c.password
+	c.mu.RUnlock()
+
+	if token != stale && token != "" {
+		return nil
+	}
+	if username == "" {
+		return errNoCredentials
+	}
+	return c.login(username, password)
+}
+
+// login logs in with a fresh nonce on each attempt since router rejects
+// reused one, logins must be serialized by loginMu.
+func (c *Client) login(username, password string) error {
+	nonce := generateNonce(c.mac)
+	for nonce == c.nonce {
+		nonce = generateNonce(c.mac)
+	}
+	c.nonce = nonce
+
 	url, err := c.buildURL("/api/xqsystem/login", false)
 	if err != nil {
 		return err
@@ -120,9 +211,9 @@ func (c *Client) Login(username, password string) error {
 
 	q := req.URL.Query()
 	q.Add("username", username)
-	q.Add("password", hashPassword(password, c.nonce))
+	q.Add("password", hashPassword(password, nonce))
 	q.Add("logtype", "2")
-	q.Add("nonce", c.nonce)
+	q.Add("nonce", nonce)
 	req.URL.RawQuery = q.Encode()
 
 	payload := struct {
@@ -136,7 +227,12 @@ func (c *Client) Login(username, password string) error {
 	if payload.Token == "" {
 		return errors.New("invalid token")
 	}
+
+	c.mu.Lock()
 	c.token = payload.Token
+	c.username = username
+	c.password = password
+	c.mu.Unlock()
 
 	return nil
 }
@@ -157,7 +253,12 @@ func (c *Client) Logout() error {
 		return err
 	}
 
+	c.mu.Lock()
 	c.token = ""
+	c.username = ""
+	c.password = ""
+	c.mu.Unlock()
+
 	return nil
 }
 
@@ -165,51 +266,175 @@ func (c *Client) Logout() error {
 func (c *Client) Status() (Stat, error) {
 	var stat Stat
 
-	url, err := c.buildURL("/api/misystem/status", true)
-	if err != nil {
+	if err := c.get("/api/misystem/status", nil, &stat); err != nil {
 		return stat, err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	return stat, nil
+}
+
+// BandwidthTest makes bandwidth testing or returns last history result.
+func (c *Client) BandwidthTest(history bool) (Band, error) {
+	var band Band
+
+	params := url.Values{}
+	if history {
+		params.Set("history", "1")
+	}
+
+	if err := c.get("/api/misystem/bandwidth_test", params, &band); err != nil {
+		return band, err
+	}
+
+	return band, nil
+}
+
+// APIError is an error returned by MiWIFI API with a non-zero response code.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api error: code %d", e.Code)
+	}
+	return fmt.Sprintf("api error: code %d: %s", e.Code, e.Message)
+}
+
+// HTTPError is an error of unexpected HTTP response status.
+type HTTPError struct {
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected response status code: %d", e.StatusCode)
+}
+
+// get makes authorized GET request to the resource and checks response code.
+func (c *Client) get(resource string, params url.Values, payload interface{}) error {
+	return c.authorized(func() (*http.Request, error) {
+		return c.newRequest(http.MethodGet, resource, params)
+	}, func(req *http.Request) error {
+		return c.call(req, payload)
+	})
+}
+
+// post makes authorized POST request to the resource with form encoded params
+// and checks response code.
+func (c *Client) post(resource string, params url.Values, payload interface{}) error {
+	return c.authorized(func() (*http.Request, error) {
+		return c.newRequest(http.MethodPost, resource, params)
+	}, func(req *http.Request) error {
+		return c.call(req, payload)
+	})
+}
+
+// newRequest builds authorized GET request with params in query or POST
+// request with form encoded params.
+func (c *Client) newRequest(method, resource string, params url.Values) (*http.Request, error) {
+	url, err := c.buildURL(resource, true)
 	if err != nil {
-		return stat, fmt.Errorf("can't build request: %w", err)
+		return nil, err
 	}
 
-	if err := c.do(req, &stat); err != nil {
-		return stat, err
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader(params.Encode())
 	}
 
-	return stat, nil
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("can't build request: %w", err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req.URL.RawQuery = params.Encode()
+	}
+
+	return req, nil
 }
 
-// BandwidthTest makes bandwidth testing or returns last history result.
-func (c *Client) BandwidthTest(history bool) (Band, error) {
-	var band Band
+// authorized sends request built by newRequest, request is built and sent
+// again once after login when the token is expired.
+func (c *Client) authorized(newRequest func() (*http.Request, error), send func(*http.Request) error) error {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
 
-	url, err := c.buildURL("/api/misystem/bandwidth_test", true)
+	req, err := newRequest()
 	if err != nil {
-		return band, err
+		return err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	err = send(req)
+	if !isTokenExpired(err) {
+		return err
+	}
+
+	switch loginErr := c.relogin(token); {
+	case loginErr == errNoCredentials:
+		return err
+	case loginErr != nil:
+		return loginErr
+	}
+
+	req, err = newRequest()
 	if err != nil {
-		return band, fmt.Errorf("can't build request: %w", err)
+		return err
 	}
+	return send(req)
+}
 
-	if history {
-		q := req.URL.Query()
-		q.Add("history", "1")
-		req.URL.RawQuery = q.Encode()
+// isTokenExpired reports whether request is failed because of expired token.
+func isTokenExpired(err error) bool {
+	var apiErr *APIError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Code == http.StatusUnauthorized
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode == http.StatusUnauthorized
+	default:
+		return false
 	}
+}
 
-	if err := c.do(req, &band); err != nil {
-		return band, err
+func (c *Client) call(req *http.Request, payload interface{}) error {
+	var body json.RawMessage
+
+	if err := c.do(req, &body); err != nil {
+		return err
+	}
+	if err := checkCode(body); err != nil {
+		return err
 	}
 
-	return band, nil
+	if payload == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, payload); err != nil {
+		return fmt.Errorf("unmarshaling error: %w", err)
+	}
+
+	return nil
+}
+
+// checkCode returns APIError when response code is not zero.
+func checkCode(body json.RawMessage) error {
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return fmt.Errorf("unmarshaling error: %w", err)
+	}
+	if apiErr.Code != 0 {
+		return &apiErr
+	}
+	return nil
 }
 
-func (c Client) do(req *http.Request, payload interface{}) error {
+func (c *Client) do(req *http.Request, payload interface{}) error {
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -219,7 +444,7 @@ func (c Client) do(req *http.Request, payload interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+		return &HTTPError{StatusCode: resp.StatusCode}
 	}
 
 	if payload == nil {
@@ -239,11 +464,21 @@ func (c Client) do(req *http.Request, payload interface{}) error {
 	return nil
 }
 
+// baseURL returns router base URL.
+func (c *Client) baseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.host
+}
+
 func (c *Client) buildURL(resource string, requireAuth bool) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if requireAuth && c.token == "" {
 		return "", errors.New("client is not authorized")
 	}
-	url := c.host + "/cgi-bin/luci"
+	url := strings.TrimRight(c.host, "/") + "/cgi-bin/luci"
 	if requireAuth {
 		url += "/;stok=" + c.token
 	}
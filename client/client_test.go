@@ -1,9 +1,13 @@
 package client
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -24,9 +28,11 @@ func TestClient_Login(t *testing.T) {
 			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/api/xqsystem/login")
 			// Expected query params
 			assert.Equal(t, username, r.URL.Query().Get("username"))
-			assert.Equal(t, hashPassword(password, nonce), r.URL.Query().Get("password"))
+			sent := r.URL.Query().Get("nonce")
+			assert.NotEqual(t, nonce, sent)
+			assert.Contains(t, sent, "_00:11:22:33:44:55_")
+			assert.Equal(t, hashPassword(password, sent), r.URL.Query().Get("password"))
 			assert.Equal(t, "2", r.URL.Query().Get("logtype"))
-			assert.Equal(t, nonce, r.URL.Query().Get("nonce"))
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(200)
@@ -37,6 +43,7 @@ func TestClient_Login(t *testing.T) {
 		c := Client{
 			httpClient: http.DefaultClient,
 			host:       ts.URL,
+			mac:        "00:11:22:33:44:55",
 			nonce:      nonce,
 		}
 
@@ -44,6 +51,32 @@ func TestClient_Login(t *testing.T) {
 		assert.Equal(t, "token", c.token)
 	})
 
+	t.Run("consecutive logins", func(t *testing.T) {
+		var nonces []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonces = append(nonces, r.URL.Query().Get("nonce"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"token": "token"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			mac:        "00:11:22:33:44:55",
+		}
+
+		assert.NoError(t, c.Login(username, password))
+		assert.NoError(t, c.Login(username, password))
+
+		// Expected fresh nonce on each login
+		if assert.Len(t, nonces, 2) {
+			assert.NotEqual(t, nonces[0], nonces[1])
+		}
+	})
+
 	t.Run("invalid response", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Expected headers
@@ -314,7 +347,7 @@ func TestClient_Status(t *testing.T) {
 			WAN: WANStat{
 				DownSpeed:        4137,
 				MaxDownloadSpeed: 533653,
-				History:          "0,200829,180511,239543,259868,429",
+				History:          History{0, 200829, 180511, 239543, 259868, 429},
 				Name:             "eth0.2",
 				Upload:           2320806776,
 				UpSpeed:          1937,
@@ -324,6 +357,24 @@ func TestClient_Status(t *testing.T) {
 		}, stat)
 	})
 
+	t.Run("corrupt history", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"code":0,"wan":{"downspeed":"4137","history":"10,oops,30"}}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		stat, err := c.Status()
+		assert.NoError(t, err)
+		assert.Equal(t, History{10, 30}, stat.WAN.History)
+	})
+
 	t.Run("client error", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Expected path
@@ -365,6 +416,25 @@ func TestClient_Status(t *testing.T) {
 	})
 }
 
+func TestHistory_UnmarshalJSON(t *testing.T) {
+	var h History
+
+	assert.NoError(t, json.Unmarshal([]byte(`"1,2, 3,"`), &h))
+	assert.Equal(t, History{1, 2, 3}, h)
+
+	assert.NoError(t, json.Unmarshal([]byte(`""`), &h))
+	assert.Empty(t, h)
+
+	assert.NoError(t, json.Unmarshal([]byte(`"1,x,-2,3"`), &h))
+	assert.Equal(t, History{1, 3}, h)
+
+	assert.NoError(t, json.Unmarshal([]byte(`[1, 2]`), &h))
+	assert.Empty(t, h)
+
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &h))
+	assert.Empty(t, h)
+}
+
 func TestClient_BandwidthTest(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -469,3 +539,137 @@ func TestClient_BandwidthTest(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// Concurrency tests are meaningful with race detector: go test -race.
+
+func TestClient_Relogin(t *testing.T) {
+	newServer := func(logins *int32, loginStatus int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/api/xqsystem/login":
+				atomic.AddInt32(logins, 1)
+				// Keep other requests waiting for login
+				time.Sleep(50 * time.Millisecond)
+				w.WriteHeader(loginStatus)
+				w.Write([]byte(`{"token": "new"}`))
+			case "/cgi-bin/luci/;stok=new/api/misystem/status":
+				w.WriteHeader(200)
+				w.Write([]byte(`{"count": {"all": 1, "online": 1}, "code": 0}`))
+			default:
+				w.WriteHeader(200)
+				w.Write([]byte(`{"code": 401, "msg": "Invalid token"}`))
+			}
+		}))
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		var logins int32
+		ts := newServer(&logins, 200)
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "old",
+			username:   "admin",
+			password:   "admin",
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stat, err := c.Status()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, stat.Count.Online)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	})
+
+	t.Run("login error", func(t *testing.T) {
+		var logins int32
+		ts := newServer(&logins, http.StatusInternalServerError)
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "old",
+			username:   "admin",
+			password:   "admin",
+		}
+
+		_, err := c.Status()
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		var logins int32
+		ts := newServer(&logins, 200)
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "old",
+		}
+
+		_, err := c.Status()
+		assert.IsType(t, &APIError{}, err)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&logins))
+	})
+}
+
+func TestClient_Concurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"token": "token", "code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+	}
+	assert.NoError(t, c.Login("admin", "admin"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			c.Status()
+		}()
+		go func() {
+			defer wg.Done()
+			c.BandwidthTest(true)
+		}()
+		go func() {
+			defer wg.Done()
+			c.Login("admin", "admin")
+		}()
+		go func() {
+			defer wg.Done()
+			c.Logout()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDeviceStat_IsOnline(t *testing.T) {
+	assert.True(t, DeviceStat{Online: "168914"}.IsOnline())
+	assert.False(t, DeviceStat{Online: "0"}.IsOnline())
+	assert.False(t, DeviceStat{Online: ""}.IsOnline())
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ConnType is a device connection type.
+type ConnType int
+
+// Device connection types.
+const (
+	ConnWired ConnType = 0
+	Conn24G   ConnType = 1
+	Conn5G    ConnType = 2
+	ConnGuest ConnType = 3
+)
+
+func (t ConnType) String() string {
+	switch t {
+	case ConnWired:
+		return "wired"
+	case Conn24G:
+		return "2.4 GHz"
+	case Conn5G:
+		return "5 GHz"
+	case ConnGuest:
+		return "guest"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
+
+// Wireless reports whether device is connected over Wi-Fi.
+func (t ConnType) Wireless() bool {
+	return t != ConnWired
+}
+
+// DeviceInfo is a known device entity of the full device list.
+type DeviceInfo struct {
+	Mac    string     `json:"mac"`
+	Name   string     `json:"name"`
+	Online int        `json:"online"`
+	Type   ConnType   `json:"type"`
+	IP     []DeviceIP `json:"ip"`
+	Signal int        `json:"signal"`
+	Rate   int        `json:"rate"`
+}
+
+// DeviceIP is a device IP address entity.
+type DeviceIP struct {
+	IP string `json:"ip"`
+}
+
+// IPs returns comma separated device IP addresses.
+func (d DeviceInfo) IPs() string {
+	ips := make([]string, 0, len(d.IP))
+	for _, ip := range d.IP {
+		ips = append(ips, ip.IP)
+	}
+	return strings.Join(ips, ", ")
+}
+
+// DeviceList returns full list of known devices with connection details,
+// signal strength is RSSI in dBm and rate is negotiated rate in Mbit/s.
+func (c *Client) DeviceList() ([]DeviceInfo, error) {
+	payload := struct {
+		List []DeviceInfo `json:"list"`
+	}{}
+
+	if err := c.get("/api/misystem/devicelist", nil, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.List, nil
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_DeviceList(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/devicelist")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"list": [
+						{
+							"mac": "00:11:22:33:44:55",
+							"name": "laptop",
+							"online": 1,
+							"type": 2,
+							"ip": [{"ip": "192.168.31.10"}, {"ip": "192.168.31.11"}],
+							"signal": -54,
+							"rate": 866
+						},
+						{
+							"mac": "55:44:33:22:11:00",
+							"name": "nas",
+							"online": 1,
+							"type": 0,
+							"ip": [{"ip": "192.168.31.2"}]
+						}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		devices, err := c.DeviceList()
+		assert.NoError(t, err)
+		assert.Equal(t, []DeviceInfo{
+			{
+				Mac:    "00:11:22:33:44:55",
+				Name:   "laptop",
+				Online: 1,
+				Type:   Conn5G,
+				IP:     []DeviceIP{{IP: "192.168.31.10"}, {IP: "192.168.31.11"}},
+				Signal: -54,
+				Rate:   866,
+			},
+			{
+				Mac:    "55:44:33:22:11:00",
+				Name:   "nas",
+				Online: 1,
+				Type:   ConnWired,
+				IP:     []DeviceIP{{IP: "192.168.31.2"}},
+			},
+		}, devices)
+		assert.Equal(t, "192.168.31.10, 192.168.31.11", devices[0].IPs())
+		assert.Equal(t, "5 GHz", devices[0].Type.String())
+		assert.False(t, devices[1].Type.Wireless())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.DeviceList()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DHCPLease is a dynamic DHCP lease entity.
+type DHCPLease struct {
+	Mac      string `json:"mac"`
+	IP       string `json:"ip"`
+	Hostname string `json:"hostname"`
+	Expires  int64  `json:"expires"`
+}
+
+// ExpiresAt returns lease expiration time, zero time is returned for
+// leases which never expire.
+func (l DHCPLease) ExpiresAt() time.Time {
+	if l.Expires == 0 {
+		return time.Time{}
+	}
+	return time.Unix(l.Expires, 0)
+}
+
+// StaticLease is a static DHCP reservation entity.
+type StaticLease struct {
+	Mac  string `json:"mac"`
+	IP   string `json:"ip"`
+	Name string `json:"name"`
+}
+
+// DHCPLeases returns DHCP server lease table.
+func (c *Client) DHCPLeases() ([]DHCPLease, error) {
+	payload := struct {
+		Leases []DHCPLease `json:"leases"`
+	}{}
+
+	if err := c.get("/api/xqnetwork/dhcp_leases", nil, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Leases, nil
+}
+
+// StaticLeases returns static DHCP reservations.
+func (c *Client) StaticLeases() ([]StaticLease, error) {
+	payload := struct {
+		List []StaticLease `json:"list"`
+	}{}
+
+	if err := c.get("/api/xqnetwork/macbind_info", nil, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.List, nil
+}
+
+// AddStaticLease reserves IP address for the device MAC address.
+func (c *Client) AddStaticLease(lease StaticLease) error {
+	data, err := json.Marshal([]StaticLease{lease})
+	if err != nil {
+		return fmt.Errorf("marshaling error: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("data", string(data))
+
+	return c.post("/api/xqnetwork/mac_bind", params, nil)
+}
+
+// RemoveStaticLease removes IP address reservation of the device MAC address.
+func (c *Client) RemoveStaticLease(mac string) error {
+	params := url.Values{}
+	params.Set("mac", mac)
+
+	return c.post("/api/xqnetwork/mac_unbind", params, nil)
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_DHCPLeases(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/dhcp_leases")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"leases": [
+						{"mac": "00:11:22:33:44:55", "ip": "192.168.31.10", "hostname": "laptop", "expires": 1588000000}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		leases, err := c.DHCPLeases()
+		assert.NoError(t, err)
+		assert.Equal(t, []DHCPLease{
+			{Mac: "00:11:22:33:44:55", IP: "192.168.31.10", Hostname: "laptop", Expires: 1588000000},
+		}, leases)
+		assert.Equal(t, int64(1588000000), leases[0].ExpiresAt().Unix())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.DHCPLeases()
+		assert.Error(t, err)
+	})
+}
+
+func TestDHCPLease_ExpiresAt(t *testing.T) {
+	assert.Equal(t, int64(1588000000), DHCPLease{Expires: 1588000000}.ExpiresAt().Unix())
+	assert.True(t, DHCPLease{Expires: 0}.ExpiresAt().IsZero())
+}
+
+func TestClient_StaticLeases(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/macbind_info")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`
+			{
+				"list": [
+					{"mac": "00:11:22:33:44:55", "ip": "192.168.31.10", "name": "laptop"}
+				],
+				"code": 0
+			}
+		`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	leases, err := c.StaticLeases()
+	assert.NoError(t, err)
+	assert.Equal(t, []StaticLease{
+		{Mac: "00:11:22:33:44:55", IP: "192.168.31.10", Name: "laptop"},
+	}, leases)
+}
+
+func TestClient_AddStaticLease(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/mac_bind")
+		// Expected form params
+		assert.JSONEq(t, `[{"mac": "00:11:22:33:44:55", "ip": "192.168.31.10", "name": "laptop"}]`, r.FormValue("data"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.AddStaticLease(StaticLease{Mac: "00:11:22:33:44:55", IP: "192.168.31.10", Name: "laptop"}))
+}
+
+func TestClient_RemoveStaticLease(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/mac_unbind")
+		// Expected form params
+		assert.Equal(t, "00:11:22:33:44:55", r.FormValue("mac"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.RemoveStaticLease("00:11:22:33:44:55"))
+}
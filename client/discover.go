@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DiscoveryHostname is a hostname which MiWiFi routers resolve to themselves.
+const DiscoveryHostname = "miwifi.com"
+
+// routeFile is a Linux IPv4 routing table.
+const routeFile = "/proc/net/route"
+
+// loginPageLimit is a maximum size of the login page read during discovery.
+const loginPageLimit = 64 << 10
+
+// routerMarkers are strings which identify MiWiFi login page.
+var routerMarkers = []string{"miwifi", "xiaomi", "小米路由器"}
+
+// DiscoveryCandidates returns hosts where the router may be found: the
+// default gateway and the well-known hostname unless it resolves to the
+// gateway.
+func DiscoveryCandidates() []string {
+	var hosts []string
+
+	gateway, err := DefaultGateway()
+	if err == nil {
+		hosts = append(hosts, "http://"+gateway.String())
+	}
+
+	addrs, err := net.LookupHost(DiscoveryHostname)
+	if err != nil {
+		return append(hosts, "http://"+DiscoveryHostname)
+	}
+	for _, addr := range addrs {
+		if gateway != nil && net.ParseIP(addr).Equal(gateway) {
+			return hosts
+		}
+	}
+	return append(hosts, "http://"+DiscoveryHostname)
+}
+
+// DefaultGateway returns IPv4 default gateway from the routing table.
+func DefaultGateway() (net.IP, error) {
+	f, err := os.Open(routeFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseDefaultGateway(f)
+}
+
+// parseDefaultGateway finds default route in /proc/net/route formatted
+// table, addresses there are hex encoded in host (little endian) byte order.
+func parseDefaultGateway(r io.Reader) (net.IP, error) {
+	const rtfGateway = 0x2
+
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[1] != "00000000" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 16)
+		if err != nil || flags&rtfGateway == 0 {
+			continue
+		}
+
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != net.IPv4len {
+			continue
+		}
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(gateway))
+		return ip, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, errors.New("default gateway not found")
+}
+
+// IsRouter reports whether the host serves MiWiFi login page.
+func IsRouter(httpClient *http.Client, host string) bool {
+	resp, err := httpClient.Get(host + "/cgi-bin/luci/web")
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, loginPageLimit))
+	if err != nil {
+		return false
+	}
+
+	page := strings.ToLower(string(body))
+	for _, marker := range routerMarkers {
+		if strings.Contains(page, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Discover probes hosts concurrently and returns ones which are MiWiFi
+// routers in the original order.
+func Discover(httpClient *http.Client, hosts []string) []string {
+	found := make([]bool, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			found[i] = IsRouter(httpClient, host)
+		}(i, host)
+	}
+	wg.Wait()
+
+	var routers []string
+	for i, host := range hosts {
+		if found[i] {
+			routers = append(routers, host)
+		}
+	}
+	return routers
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDefaultGateway(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		table := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	0000A8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+eth0	00000000	011FA8C0	0003	0	0	100	00000000	0	0	0
+`
+		gateway, err := parseDefaultGateway(strings.NewReader(table))
+		assert.NoError(t, err)
+		assert.True(t, net.IPv4(192, 168, 31, 1).Equal(gateway))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		table := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	0000A8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+`
+		_, err := parseDefaultGateway(strings.NewReader(table))
+		assert.Error(t, err)
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, "/cgi-bin/luci/web", r.URL.Path)
+
+		w.WriteHeader(200)
+		w.Write([]byte(`<html><head><title>小米路由器</title></head><body>MiWiFi</body></html>`))
+	}))
+	defer router.Close()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write([]byte(`<html><head><title>Router</title></head></html>`))
+	}))
+	defer other.Close()
+
+	missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer missing.Close()
+
+	routers := Discover(http.DefaultClient, []string{other.URL, router.URL, missing.URL, "http://127.0.0.1:0"})
+	assert.Equal(t, []string{router.URL}, routers)
+}
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/sha1"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math/rand"
@@ -10,6 +14,9 @@ import (
 
 const key = "a2ffa5c9be07488bbb04a3a47d3c5f6a"
 
+// passwordIV is an AES IV the router web UI encrypts new password with.
+const passwordIV = "64175472480004614961023454661220"
+
 func generateNonce(macAddress string) string {
 	t := time.Now().Unix()
 	r := rand.Float64() * 10e3
@@ -20,15 +27,38 @@ func generateNonce(macAddress string) string {
 func hashPassword(password, nonce string) string {
 	h := sha1.New()
 
+	h.Write([]byte(nonce))
+	h.Write([]byte(storedPassword(password)))
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	return hash
+}
+
+// storedPassword returns password hash in the form router keeps it,
+// it's salted with nonce by hashPassword to prove password knowledge.
+func storedPassword(password string) string {
+	h := sha1.New()
+
 	h.Write([]byte(password))
 	h.Write([]byte(key))
-	hash := hex.EncodeToString(h.Sum(nil))
 
-	h.Reset()
+	return hex.EncodeToString(h.Sum(nil))
+}
 
-	h.Write([]byte(nonce))
-	h.Write([]byte(hash))
-	hash = hex.EncodeToString(h.Sum(nil))
+// encryptPassword returns new password hash encrypted like the router web
+// UI does on password change: AES-128-CBC with the key taken from the old
+// password nonce hash, so the hash isn't sent in clear text.
+func encryptPassword(oldPassword, newPassword, nonce string) string {
+	secret, _ := hex.DecodeString(hashPassword(oldPassword, nonce)[:32])
+	iv, _ := hex.DecodeString(passwordIV)
 
-	return hash
+	plain := []byte(storedPassword(newPassword))
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	plain = append(plain, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	block, _ := aes.NewCipher(secret)
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, plain)
+
+	return base64.StdEncoding.EncodeToString(encrypted)
 }
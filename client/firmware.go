@@ -0,0 +1,69 @@
+package client
+
+// ROMUpdate is a firmware update check result entity.
+type ROMUpdate struct {
+	NeedUpdate  int    `json:"needUpdate"`
+	Version     string `json:"version"`
+	ChangeLog   string `json:"changeLog"`
+	Size        uint64 `json:"size"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+// Available reports whether a newer firmware version is available.
+func (u ROMUpdate) Available() bool {
+	return u.NeedUpdate == 1
+}
+
+// UpgradeStatus is a firmware upgrade progress entity.
+type UpgradeStatus struct {
+	Status  int `json:"status"`
+	Percent int `json:"percent"`
+}
+
+// Firmware upgrade statuses.
+const (
+	UpgradeIdle        = 0
+	UpgradeChecking    = 1
+	UpgradeDownloading = 2
+	UpgradeFlashing    = 3
+	UpgradeFailed      = 4
+)
+
+func (s UpgradeStatus) String() string {
+	switch s.Status {
+	case UpgradeIdle:
+		return "idle"
+	case UpgradeChecking:
+		return "checking"
+	case UpgradeDownloading:
+		return "downloading"
+	case UpgradeFlashing:
+		return "flashing"
+	case UpgradeFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckROMUpdate checks whether router firmware update is available.
+func (c *Client) CheckROMUpdate() (ROMUpdate, error) {
+	var update ROMUpdate
+
+	if err := c.get("/api/xqsystem/check_rom_update", nil, &update); err != nil {
+		return update, err
+	}
+
+	return update, nil
+}
+
+// UpgradeStatus returns firmware upgrade progress.
+func (c *Client) UpgradeStatus() (UpgradeStatus, error) {
+	var status UpgradeStatus
+
+	if err := c.get("/api/xqsystem/upgrade_status", nil, &status); err != nil {
+		return status, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CheckROMUpdate(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/check_rom_update")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"needUpdate": 1,
+					"version": "3.0.24",
+					"changeLog": "Security fixes",
+					"size": 7340032,
+					"downloadUrl": "http://cdn.example.com/rom.bin",
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		update, err := c.CheckROMUpdate()
+		assert.NoError(t, err)
+		assert.True(t, update.Available())
+		assert.Equal(t, ROMUpdate{
+			NeedUpdate:  1,
+			Version:     "3.0.24",
+			ChangeLog:   "Security fixes",
+			Size:        7340032,
+			DownloadURL: "http://cdn.example.com/rom.bin",
+		}, update)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"needUpdate": 0, "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		update, err := c.CheckROMUpdate()
+		assert.NoError(t, err)
+		assert.False(t, update.Available())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.CheckROMUpdate()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_UpgradeStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/upgrade_status")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"status": 2, "percent": 45, "code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	status, err := c.UpgradeStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, UpgradeStatus{Status: UpgradeDownloading, Percent: 45}, status)
+	assert.Equal(t, "downloading", status.String())
+}
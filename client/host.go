@@ -0,0 +1,44 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ParseHost parses router address into base URL: scheme defaults to http,
+// custom port and base path (e.g. behind reverse proxy) are kept, bare IPv6
+// literals are accepted with optional zone.
+func ParseHost(host string) (*url.URL, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return nil, errors.New("empty host")
+	}
+
+	if !strings.Contains(host, "://") {
+		if ip := strings.SplitN(host, "%", 2); net.ParseIP(ip[0]) != nil && strings.Contains(ip[0], ":") {
+			host = "[" + strings.Replace(host, "%", "%25", 1) + "]"
+		}
+		host = "http://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host: %w", err)
+	}
+
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return nil, fmt.Errorf("unsupported scheme: %q", u.Scheme)
+	case u.Hostname() == "":
+		return nil, fmt.Errorf("invalid host: %q", host)
+	case u.User != nil, u.RawQuery != "", u.Fragment != "":
+		return nil, fmt.Errorf("host must not have credentials, query or fragment: %q", host)
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u, nil
+}
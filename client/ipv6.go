@@ -0,0 +1,112 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// IPv6 connection modes.
+const (
+	IPv6ModeOff    = "off"
+	IPv6ModeNative = "native"
+	IPv6ModeStatic = "static"
+	IPv6ModeNAT    = "nat"
+)
+
+// IPv6Info is a router IPv6 configuration and state entity.
+type IPv6Info struct {
+	Mode       string   `json:"mode"`
+	Up         bool     `json:"up"`
+	Prefix     string   `json:"prefix"`
+	WANAddress []string `json:"wan_ip6"`
+	LANAddress []string `json:"lan_ip6"`
+	Gateway    string   `json:"gateway"`
+	DNS        []string `json:"dns"`
+}
+
+// Enabled reports whether IPv6 is turned on.
+func (i IPv6Info) Enabled() bool {
+	return i.Mode != "" && i.Mode != IPv6ModeOff
+}
+
+// IPv6Config is a router IPv6 configuration entity, address, gateway,
+// prefix and DNS are used in static mode only.
+type IPv6Config struct {
+	Mode    string   `json:"mode"`
+	Address string   `json:"address,omitempty"`
+	Gateway string   `json:"gateway,omitempty"`
+	Prefix  string   `json:"prefix,omitempty"`
+	DNS     []string `json:"dns,omitempty"`
+}
+
+// Validate checks that config has all fields required by its mode.
+func (cfg IPv6Config) Validate() error {
+	switch cfg.Mode {
+	case IPv6ModeOff, IPv6ModeNative, IPv6ModeNAT:
+		return nil
+	case IPv6ModeStatic:
+	default:
+		return fmt.Errorf("unknown IPv6 mode: %q", cfg.Mode)
+	}
+
+	ip, _, err := net.ParseCIDR(cfg.Address)
+	if err != nil || ip.To4() != nil {
+		return fmt.Errorf("invalid IPv6 address, expected CIDR notation: %q", cfg.Address)
+	}
+	if gw := net.ParseIP(cfg.Gateway); gw == nil || gw.To4() != nil {
+		return fmt.Errorf("invalid IPv6 gateway: %q", cfg.Gateway)
+	}
+	if _, prefix, err := net.ParseCIDR(cfg.Prefix); err != nil || prefix.IP.To4() != nil {
+		return fmt.Errorf("invalid IPv6 LAN prefix: %q", cfg.Prefix)
+	}
+	if len(cfg.DNS) == 0 {
+		return errors.New("DNS servers are required in static mode")
+	}
+	for _, dns := range cfg.DNS {
+		if net.ParseIP(dns) == nil {
+			return fmt.Errorf("invalid DNS server: %q", dns)
+		}
+	}
+
+	return nil
+}
+
+// IPv6Info returns router IPv6 configuration and state.
+func (c *Client) IPv6Info() (IPv6Info, error) {
+	payload := struct {
+		Info struct {
+			IPv6Info
+			Status int `json:"status"`
+		} `json:"info"`
+	}{}
+
+	if err := c.get("/api/xqnetwork/ipv6_status", nil, &payload); err != nil {
+		return IPv6Info{}, err
+	}
+
+	info := payload.Info.IPv6Info
+	info.Up = payload.Info.Status == 1
+
+	return info, nil
+}
+
+// SetIPv6Config validates and applies router IPv6 configuration.
+func (c *Client) SetIPv6Config(cfg IPv6Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("mode", cfg.Mode)
+	if cfg.Mode == IPv6ModeStatic {
+		params.Set("ip6addr", cfg.Address)
+		params.Set("ip6gw", cfg.Gateway)
+		params.Set("ip6prefix", cfg.Prefix)
+		params.Set("dns", strings.Join(cfg.DNS, ","))
+	}
+
+	return c.post("/api/xqnetwork/set_wan6", params, nil)
+}
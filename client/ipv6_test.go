@@ -0,0 +1,135 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPv6Config_Validate(t *testing.T) {
+	for name, cfg := range map[string]IPv6Config{
+		"off":    {Mode: IPv6ModeOff},
+		"native": {Mode: IPv6ModeNative},
+		"nat":    {Mode: IPv6ModeNAT},
+		"static": {
+			Mode:    IPv6ModeStatic,
+			Address: "2001:db8::2/64",
+			Gateway: "2001:db8::1",
+			Prefix:  "2001:db8:1::/64",
+			DNS:     []string{"2001:4860:4860::8888"},
+		},
+	} {
+		assert.NoError(t, cfg.Validate(), name)
+	}
+
+	for name, cfg := range map[string]IPv6Config{
+		"unknown mode":   {Mode: "6to4"},
+		"ipv4 address":   {Mode: IPv6ModeStatic, Address: "192.0.2.1/24", Gateway: "2001:db8::1", Prefix: "2001:db8:1::/64", DNS: []string{"::1"}},
+		"no prefix len":  {Mode: IPv6ModeStatic, Address: "2001:db8::2", Gateway: "2001:db8::1", Prefix: "2001:db8:1::/64", DNS: []string{"::1"}},
+		"invalid prefix": {Mode: IPv6ModeStatic, Address: "2001:db8::2/64", Gateway: "2001:db8::1", Prefix: "2001:db8:1::", DNS: []string{"::1"}},
+		"no dns":         {Mode: IPv6ModeStatic, Address: "2001:db8::2/64", Gateway: "2001:db8::1", Prefix: "2001:db8:1::/64"},
+	} {
+		assert.Error(t, cfg.Validate(), name)
+	}
+}
+
+func TestClient_IPv6Info(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/ipv6_status")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"info": {
+						"mode": "native",
+						"status": 1,
+						"prefix": "2001:db8:1200::/56",
+						"wan_ip6": ["2001:db8:ff::2/64"],
+						"lan_ip6": ["2001:db8:1200::1/64"],
+						"gateway": "fe80::1",
+						"dns": ["2001:db8:ff::53"]
+					},
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		info, err := c.IPv6Info()
+		assert.NoError(t, err)
+		assert.True(t, info.Enabled())
+		assert.Equal(t, IPv6Info{
+			Mode:       IPv6ModeNative,
+			Up:         true,
+			Prefix:     "2001:db8:1200::/56",
+			WANAddress: []string{"2001:db8:ff::2/64"},
+			LANAddress: []string{"2001:db8:1200::1/64"},
+			Gateway:    "fe80::1",
+			DNS:        []string{"2001:db8:ff::53"},
+		}, info)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.IPv6Info()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetIPv6Config(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/set_wan6")
+		// Expected form params
+		assert.Equal(t, "static", r.FormValue("mode"))
+		assert.Equal(t, "2001:db8::2/64", r.FormValue("ip6addr"))
+		assert.Equal(t, "2001:db8::1", r.FormValue("ip6gw"))
+		assert.Equal(t, "2001:db8:1::/64", r.FormValue("ip6prefix"))
+		assert.Equal(t, "2001:db8::53,2001:db8::54", r.FormValue("dns"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetIPv6Config(IPv6Config{
+		Mode:    IPv6ModeStatic,
+		Address: "2001:db8::2/64",
+		Gateway: "2001:db8::1",
+		Prefix:  "2001:db8:1::/64",
+		DNS:     []string{"2001:db8::53", "2001:db8::54"},
+	}))
+}
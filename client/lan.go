@@ -0,0 +1,205 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LANConfig is a LAN address and DHCP server configuration entity,
+// DHCP pool bounds are full IP addresses inside the LAN subnet.
+type LANConfig struct {
+	IP          string `json:"ip"`
+	Mask        string `json:"mask"`
+	DHCPEnabled bool   `json:"dhcp"`
+	PoolStart   string `json:"pool_start"`
+	PoolEnd     string `json:"pool_end"`
+	LeaseTime   string `json:"lease_time"`
+}
+
+// Validate checks that router IP and DHCP pool belong to the LAN subnet
+// and router IP is not in the pool.
+func (cfg LANConfig) Validate() error {
+	ip := net.ParseIP(cfg.IP).To4()
+	if ip == nil {
+		return fmt.Errorf("invalid LAN IP: %q", cfg.IP)
+	}
+	mask, err := parseMask(cfg.Mask)
+	if err != nil {
+		return err
+	}
+
+	subnet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	host := ipToUint(ip) &^ ipToUint(net.IP(mask))
+	if host == 0 || host == ^ipToUint(net.IP(mask)) {
+		return fmt.Errorf("LAN IP %s is a network or broadcast address of %s", cfg.IP, subnet.String())
+	}
+
+	start := net.ParseIP(cfg.PoolStart).To4()
+	if start == nil {
+		return fmt.Errorf("invalid DHCP pool start: %q", cfg.PoolStart)
+	}
+	end := net.ParseIP(cfg.PoolEnd).To4()
+	if end == nil {
+		return fmt.Errorf("invalid DHCP pool end: %q", cfg.PoolEnd)
+	}
+	if !subnet.Contains(start) || !subnet.Contains(end) {
+		return fmt.Errorf("DHCP pool %s-%s is outside of %s", cfg.PoolStart, cfg.PoolEnd, subnet.String())
+	}
+	if ipToUint(start) > ipToUint(end) {
+		return fmt.Errorf("DHCP pool start %s is after end %s", cfg.PoolStart, cfg.PoolEnd)
+	}
+	if ipToUint(start) <= ipToUint(ip) && ipToUint(ip) <= ipToUint(end) {
+		return fmt.Errorf("LAN IP %s is inside DHCP pool %s-%s", cfg.IP, cfg.PoolStart, cfg.PoolEnd)
+	}
+
+	return nil
+}
+
+// lanInfoPayload is a raw LAN info API response.
+type lanInfoPayload struct {
+	Info struct {
+		IPv4 []struct {
+			IP   string `json:"ip"`
+			Mask string `json:"mask"`
+		} `json:"ipv4"`
+	} `json:"info"`
+}
+
+// lanDHCPPayload is a raw LAN DHCP server API response, pool bounds are
+// host numbers inside the LAN subnet.
+type lanDHCPPayload struct {
+	Info struct {
+		Ignore    string `json:"ignore"`
+		LeaseTime string `json:"leasetime"`
+		Start     string `json:"start"`
+		End       string `json:"end"`
+	} `json:"info"`
+}
+
+// LANConfig returns LAN address and DHCP server configuration.
+func (c *Client) LANConfig() (LANConfig, error) {
+	var cfg LANConfig
+
+	var info lanInfoPayload
+	if err := c.get("/api/xqnetwork/lan_info", nil, &info); err != nil {
+		return cfg, err
+	}
+	if len(info.Info.IPv4) == 0 {
+		return cfg, errors.New("no LAN address in response")
+	}
+	cfg.IP = info.Info.IPv4[0].IP
+	cfg.Mask = info.Info.IPv4[0].Mask
+
+	var dhcp lanDHCPPayload
+	if err := c.get("/api/xqnetwork/lan_dhcp", nil, &dhcp); err != nil {
+		return cfg, err
+	}
+	cfg.DHCPEnabled = dhcp.Info.Ignore != "1"
+	cfg.LeaseTime = dhcp.Info.LeaseTime
+
+	ip := net.ParseIP(cfg.IP).To4()
+	mask, err := parseMask(cfg.Mask)
+	if ip == nil || err != nil {
+		return cfg, fmt.Errorf("invalid LAN address %s/%s", cfg.IP, cfg.Mask)
+	}
+	hostMask := ^ipToUint(net.IP(mask))
+	for _, bound := range []struct {
+		offset string
+		addr   *string
+	}{
+		{dhcp.Info.Start, &cfg.PoolStart},
+		{dhcp.Info.End, &cfg.PoolEnd},
+	} {
+		n, err := strconv.ParseUint(bound.offset, 10, 32)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid DHCP pool bound: %q", bound.offset)
+		}
+		if uint32(n)&^hostMask != 0 {
+			return cfg, fmt.Errorf("DHCP pool bound %d is out of %s host range", n, cfg.Mask)
+		}
+		*bound.addr = uintToIP(ipToUint(ip.Mask(mask)) | uint32(n)).String()
+	}
+
+	return cfg, nil
+}
+
+// SetLANConfig validates and applies LAN address and DHCP server
+// configuration, router drops LAN connections when its address changes.
+func (c *Client) SetLANConfig(cfg LANConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	mask, _ := parseMask(cfg.Mask)
+	hostMask := ^ipToUint(net.IP(mask))
+
+	params := url.Values{}
+	params.Set("start", strconv.FormatUint(uint64(ipToUint(net.ParseIP(cfg.PoolStart).To4())&hostMask), 10))
+	params.Set("end", strconv.FormatUint(uint64(ipToUint(net.ParseIP(cfg.PoolEnd).To4())&hostMask), 10))
+	params.Set("leasetime", cfg.LeaseTime)
+	params.Set("ignore", "1")
+	if cfg.DHCPEnabled {
+		params.Set("ignore", "0")
+	}
+
+	if err := c.post("/api/xqnetwork/set_lan_dhcp", params, nil); err != nil {
+		return err
+	}
+
+	params = url.Values{}
+	params.Set("ip", cfg.IP)
+	params.Set("mask", cfg.Mask)
+
+	return c.post("/api/xqnetwork/set_lan_ip", params, nil)
+}
+
+// SetHostIP points client to the router at the new IP address keeping
+// scheme and port of the current host.
+func (c *Client) SetHostIP(ip string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u, err := url.Parse(c.host)
+	if err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+	switch port := u.Port(); {
+	case port != "":
+		u.Host = net.JoinHostPort(ip, port)
+	case strings.Contains(ip, ":"):
+		u.Host = "[" + ip + "]"
+	default:
+		u.Host = ip
+	}
+	c.host = u.String()
+	c.token = ""
+	return nil
+}
+
+// parseMask parses dotted decimal IPv4 netmask.
+func parseMask(s string) (net.IPMask, error) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid netmask: %q", s)
+	}
+	mask := net.IPMask(ip)
+	if _, bits := mask.Size(); bits == 0 {
+		return nil, fmt.Errorf("invalid netmask: %q", s)
+	}
+	return mask, nil
+}
+
+func ipToUint(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func uintToIP(n uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
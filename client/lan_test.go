@@ -0,0 +1,190 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLANConfig_Validate(t *testing.T) {
+	valid := LANConfig{
+		IP:        "192.168.31.1",
+		Mask:      "255.255.255.0",
+		PoolStart: "192.168.31.5",
+		PoolEnd:   "192.168.31.254",
+	}
+	assert.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(cfg *LANConfig){
+		"invalid ip":        func(cfg *LANConfig) { cfg.IP = "192.168.31" },
+		"invalid mask":      func(cfg *LANConfig) { cfg.Mask = "255.0.255.0" },
+		"network address":   func(cfg *LANConfig) { cfg.IP = "192.168.31.0" },
+		"broadcast address": func(cfg *LANConfig) { cfg.IP = "192.168.31.255" },
+		"pool outside":      func(cfg *LANConfig) { cfg.PoolEnd = "192.168.32.10" },
+		"pool reversed":     func(cfg *LANConfig) { cfg.PoolStart, cfg.PoolEnd = cfg.PoolEnd, cfg.PoolStart },
+		"ip in pool":        func(cfg *LANConfig) { cfg.IP = "192.168.31.100" },
+	} {
+		cfg := valid
+		modify(&cfg)
+		assert.Error(t, cfg.Validate(), name)
+	}
+}
+
+func TestClient_LANConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/lan_info":
+				w.Write([]byte(`{"info": {"ipv4": [{"ip": "192.168.31.1", "mask": "255.255.255.0"}]}, "code": 0}`))
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/lan_dhcp":
+				w.Write([]byte(`{"info": {"ignore": "0", "leasetime": "12h", "start": "5", "end": "254"}, "code": 0}`))
+			default:
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		cfg, err := c.LANConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, LANConfig{
+			IP:          "192.168.31.1",
+			Mask:        "255.255.255.0",
+			DHCPEnabled: true,
+			PoolStart:   "192.168.31.5",
+			PoolEnd:     "192.168.31.254",
+			LeaseTime:   "12h",
+		}, cfg)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.LANConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("pool bound out of mask", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/lan_info":
+				w.Write([]byte(`{"info": {"ipv4": [{"ip": "192.168.31.1", "mask": "255.255.255.128"}]}, "code": 0}`))
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/lan_dhcp":
+				w.Write([]byte(`{"info": {"ignore": "0", "leasetime": "12h", "start": "5", "end": "200"}, "code": 0}`))
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.LANConfig()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetLANConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var paths []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/set_lan_dhcp":
+				assert.Equal(t, "100", r.FormValue("start"))
+				assert.Equal(t, "200", r.FormValue("end"))
+				assert.Equal(t, "24h", r.FormValue("leasetime"))
+				assert.Equal(t, "0", r.FormValue("ignore"))
+			case "/cgi-bin/luci/;stok=token/api/xqnetwork/set_lan_ip":
+				assert.Equal(t, "10.0.0.1", r.FormValue("ip"))
+				assert.Equal(t, "255.255.0.0", r.FormValue("mask"))
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.SetLANConfig(LANConfig{
+			IP:          "10.0.0.1",
+			Mask:        "255.255.0.0",
+			DHCPEnabled: true,
+			PoolStart:   "10.0.0.100",
+			PoolEnd:     "10.0.0.200",
+			LeaseTime:   "24h",
+		}))
+		assert.Equal(t, []string{
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/set_lan_dhcp",
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/set_lan_ip",
+		}, paths)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       "http://127.0.0.1:0",
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		err := c.SetLANConfig(LANConfig{
+			IP:        "10.0.0.150",
+			Mask:      "255.255.255.0",
+			PoolStart: "10.0.0.100",
+			PoolEnd:   "10.0.0.200",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetHostIP(t *testing.T) {
+	c := Client{host: "http://192.168.31.1", token: "token"}
+	assert.NoError(t, c.SetHostIP("10.0.0.1"))
+	assert.Equal(t, "http://10.0.0.1", c.host)
+	assert.Equal(t, "", c.token)
+
+	c = Client{host: "https://192.168.31.1:8443"}
+	assert.NoError(t, c.SetHostIP("10.0.0.1"))
+	assert.Equal(t, "https://10.0.0.1:8443", c.host)
+
+	c = Client{host: "https://proxy.example.com/router"}
+	assert.NoError(t, c.SetHostIP("fd00::1"))
+	assert.Equal(t, "https://[fd00::1]/router", c.host)
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Weekdays is a set of week days where 1 is Monday and 7 is Sunday.
+type Weekdays []int
+
+// ParseWeekdays parses week days list like "1-5" or "1,3,6-7".
+func ParseWeekdays(s string) (Weekdays, error) {
+	var days Weekdays
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil || from < 1 || from > 7 {
+			return nil, fmt.Errorf("invalid week day: %q", part)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || to < from || to > 7 {
+				return nil, fmt.Errorf("invalid week days range: %q", part)
+			}
+		}
+
+		for d := from; d <= to; d++ {
+			if !seen[d] {
+				seen[d] = true
+				days = append(days, d)
+			}
+		}
+	}
+
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no week days in %q", s)
+	}
+	return days, nil
+}
+
+// Contains reports whether day is in the set.
+func (w Weekdays) Contains(day int) bool {
+	for _, d := range w {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Weekdays) String() string {
+	days := make([]string, 0, len(w))
+	for _, d := range w {
+		days = append(days, strconv.Itoa(d))
+	}
+	return strings.Join(days, " ")
+}
+
+// UnmarshalJSON decodes week days from space separated string.
+func (w *Weekdays) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*w = nil
+	for _, f := range strings.Fields(s) {
+		d, err := strconv.Atoi(f)
+		if err != nil {
+			return fmt.Errorf("invalid week day: %w", err)
+		}
+		*w = append(*w, d)
+	}
+	return nil
+}
+
+// AccessRule is a time based rule which blocks device internet access
+// from From till To ("15:04" format) on the week days, the window may
+// cross midnight.
+type AccessRule struct {
+	ID       string   `json:"id"`
+	Mac      string   `json:"mac"`
+	Weekdays Weekdays `json:"weekdays"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Enabled  int      `json:"enabled"`
+}
+
+// URLFilter is a device URL filter entity, mode is "black" to block
+// the URLs or "white" to allow only them.
+type URLFilter struct {
+	Mode string   `json:"mode"`
+	URLs []string `json:"urls"`
+}
+
+// AccessRules returns device internet access schedule rules.
+func (c *Client) AccessRules(mac string) ([]AccessRule, error) {
+	params := url.Values{}
+	params.Set("mac", mac)
+
+	payload := struct {
+		Rules []AccessRule `json:"rules"`
+	}{}
+
+	if err := c.get("/api/misystem/parentctl_info", params, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Rules, nil
+}
+
+// AddAccessRule creates device internet access schedule rule.
+func (c *Client) AddAccessRule(rule AccessRule) error {
+	params := url.Values{}
+	params.Set("mac", rule.Mac)
+	params.Set("weekdays", rule.Weekdays.String())
+	params.Set("from", rule.From)
+	params.Set("to", rule.To)
+	params.Set("enabled", strconv.Itoa(rule.Enabled))
+
+	return c.post("/api/misystem/parentctl_add", params, nil)
+}
+
+// DeleteAccessRule removes device internet access schedule rule.
+func (c *Client) DeleteAccessRule(mac, id string) error {
+	params := url.Values{}
+	params.Set("mac", mac)
+	params.Set("id", id)
+
+	return c.post("/api/misystem/parentctl_del", params, nil)
+}
+
+// URLFilter returns device URL filter, firmwares without URL filtering
+// respond with APIError.
+func (c *Client) URLFilter(mac string) (URLFilter, error) {
+	var filter URLFilter
+
+	params := url.Values{}
+	params.Set("mac", mac)
+
+	if err := c.get("/api/misystem/urlfilter_info", params, &filter); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// SetURLFilter replaces device URL filter.
+func (c *Client) SetURLFilter(mac string, filter URLFilter) error {
+	params := url.Values{}
+	params.Set("mac", mac)
+	params.Set("mode", filter.Mode)
+	params.Set("urls", strings.Join(filter.URLs, ","))
+
+	return c.post("/api/misystem/urlfilter_set", params, nil)
+}
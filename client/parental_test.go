@@ -0,0 +1,224 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	days, err := ParseWeekdays("1-5")
+	assert.NoError(t, err)
+	assert.Equal(t, Weekdays{1, 2, 3, 4, 5}, days)
+	assert.Equal(t, "1 2 3 4 5", days.String())
+
+	days, err = ParseWeekdays("1, 3, 6-7, 3")
+	assert.NoError(t, err)
+	assert.Equal(t, Weekdays{1, 3, 6, 7}, days)
+	assert.True(t, days.Contains(6))
+	assert.False(t, days.Contains(2))
+
+	for _, s := range []string{"", "0", "8", "5-1", "mon"} {
+		_, err = ParseWeekdays(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestClient_AccessRules(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/parentctl_info")
+			// Expected query params
+			assert.Equal(t, "00:11:22:33:44:55", r.URL.Query().Get("mac"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"rules": [
+						{
+							"id": "1",
+							"mac": "00:11:22:33:44:55",
+							"weekdays": "1 2 3 4 5",
+							"from": "22:00",
+							"to": "07:00",
+							"enabled": 1
+						}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		rules, err := c.AccessRules("00:11:22:33:44:55")
+		assert.NoError(t, err)
+		assert.Equal(t, []AccessRule{
+			{
+				ID:       "1",
+				Mac:      "00:11:22:33:44:55",
+				Weekdays: Weekdays{1, 2, 3, 4, 5},
+				From:     "22:00",
+				To:       "07:00",
+				Enabled:  1,
+			},
+		}, rules)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.AccessRules("00:11:22:33:44:55")
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_AddAccessRule(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/parentctl_add")
+		// Expected form params
+		assert.Equal(t, "00:11:22:33:44:55", r.FormValue("mac"))
+		assert.Equal(t, "6 7", r.FormValue("weekdays"))
+		assert.Equal(t, "23:30", r.FormValue("from"))
+		assert.Equal(t, "08:00", r.FormValue("to"))
+		assert.Equal(t, "1", r.FormValue("enabled"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.AddAccessRule(AccessRule{
+		Mac:      "00:11:22:33:44:55",
+		Weekdays: Weekdays{6, 7},
+		From:     "23:30",
+		To:       "08:00",
+		Enabled:  1,
+	}))
+}
+
+func TestClient_DeleteAccessRule(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/parentctl_del")
+		// Expected form params
+		assert.Equal(t, "00:11:22:33:44:55", r.FormValue("mac"))
+		assert.Equal(t, "1", r.FormValue("id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.DeleteAccessRule("00:11:22:33:44:55", "1"))
+}
+
+func TestClient_URLFilter(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/urlfilter_info")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"mode": "black", "urls": ["games.example.com"], "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		filter, err := c.URLFilter("00:11:22:33:44:55")
+		assert.NoError(t, err)
+		assert.Equal(t, URLFilter{Mode: "black", URLs: []string{"games.example.com"}}, filter)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 1502, "msg": "not supported"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.URLFilter("00:11:22:33:44:55")
+		assert.IsType(t, &APIError{}, err)
+	})
+}
+
+func TestClient_SetURLFilter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/urlfilter_set")
+		// Expected form params
+		assert.Equal(t, "white", r.FormValue("mode"))
+		assert.Equal(t, "school.example.com,wiki.example.org", r.FormValue("urls"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetURLFilter("00:11:22:33:44:55", URLFilter{
+		Mode: "white",
+		URLs: []string{"school.example.com", "wiki.example.org"},
+	}))
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ChangePassword changes router admin password. Old password is hashed
+// with a fresh nonce like on login, new password is sent as the hash
+// router stores encrypted with the old password nonce hash. Current token
+// becomes invalid, kept credentials are updated to log in with the new
+// password.
+func (c *Client) ChangePassword(oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return errors.New("new password is required")
+	}
+
+	// params are built for each attempt, router rejects reused nonce
+	err := c.authorized(func() (*http.Request, error) {
+		nonce := generateNonce(c.mac)
+
+		params := url.Values{}
+		params.Set("nonce", nonce)
+		params.Set("oldPwd", hashPassword(oldPassword, nonce))
+		params.Set("newPwd", encryptPassword(oldPassword, newPassword, nonce))
+
+		return c.newRequest(http.MethodPost, "/api/xqsystem/set_name_password", params)
+	}, func(req *http.Request) error {
+		return c.call(req, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.username != "" {
+		c.password = newPassword
+	}
+	c.mu.Unlock()
+
+	return nil
+}
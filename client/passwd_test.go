@@ -0,0 +1,136 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ChangePassword(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected method
+			assert.Equal(t, "POST", r.Method)
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/set_name_password")
+			// Expected form params
+			nonce := r.FormValue("nonce")
+			assert.Contains(t, nonce, "_00:11:22:33:44:55_")
+			assert.Equal(t, hashPassword("old-secret", nonce), r.FormValue("oldPwd"))
+			newPwd := r.FormValue("newPwd")
+			assert.NotEqual(t, storedPassword("new-secret"), newPwd)
+			assert.Equal(t, storedPassword("new-secret"), decryptPassword(t, "old-secret", nonce, newPwd))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			mac:        "00:11:22:33:44:55",
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.ChangePassword("old-secret", "new-secret"))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 1553, "msg": "old password is wrong"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		err := c.ChangePassword("wrong", "new-secret")
+		assert.IsType(t, &APIError{}, err)
+	})
+
+	t.Run("relogin", func(t *testing.T) {
+		var attempts int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/api/xqsystem/login":
+				// Expected fresh login nonce
+				assert.NotEqual(t, "nonce", r.URL.Query().Get("nonce"))
+				w.Write([]byte(`{"token": "new"}`))
+			case "/cgi-bin/luci/;stok=old/api/xqsystem/set_name_password",
+				"/cgi-bin/luci/;stok=new/api/xqsystem/set_name_password":
+				attempts++
+				// Expected params built with the nonce of this attempt
+				nonce := r.FormValue("nonce")
+				assert.Equal(t, hashPassword("old-secret", nonce), r.FormValue("oldPwd"))
+				assert.Equal(t, storedPassword("new-secret"), decryptPassword(t, "old-secret", nonce, r.FormValue("newPwd")))
+
+				if strings.Contains(r.URL.Path, "stok=old") {
+					w.Write([]byte(`{"code": 401, "msg": "Invalid token"}`))
+					return
+				}
+				w.Write([]byte(`{"code": 0}`))
+			default:
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			mac:        "00:11:22:33:44:55",
+			nonce:      "nonce",
+			token:      "old",
+			username:   "admin",
+			password:   "old-secret",
+		}
+
+		assert.NoError(t, c.ChangePassword("old-secret", "new-secret"))
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, "new-secret", c.password)
+	})
+
+	t.Run("empty password", func(t *testing.T) {
+		c := Client{httpClient: http.DefaultClient, token: "token"}
+		assert.Error(t, c.ChangePassword("old-secret", ""))
+	})
+}
+
+func TestHashPassword(t *testing.T) {
+	// sha1(nonce + sha1(password + key))
+	assert.Equal(t, "e762b909a3e1c5a20ce781d4e063642150597128", hashPassword("admin", "nonce"))
+}
+
+// decryptPassword decrypts new password hash like the router does.
+func decryptPassword(t *testing.T, oldPassword, nonce, encrypted string) string {
+	secret, _ := hex.DecodeString(hashPassword(oldPassword, nonce)[:32])
+	iv, _ := hex.DecodeString(passwordIV)
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if !assert.NoError(t, err) || !assert.Zero(t, len(data)%aes.BlockSize) || len(data) == 0 {
+		return ""
+	}
+
+	block, _ := aes.NewCipher(secret)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, data)
+	return string(data[:len(data)-int(data[len(data)-1])])
+}
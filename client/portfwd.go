@@ -0,0 +1,248 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Protocol is a port forwarding rule protocol.
+type Protocol int
+
+// Port forwarding rule protocols.
+const (
+	ProtoTCP  Protocol = 1
+	ProtoUDP  Protocol = 2
+	ProtoBoth Protocol = 3
+)
+
+var protocolNames = map[Protocol]string{
+	ProtoTCP:  "tcp",
+	ProtoUDP:  "udp",
+	ProtoBoth: "both",
+}
+
+// ParseProtocol returns protocol by its name.
+func ParseProtocol(name string) (Protocol, error) {
+	for proto, protoName := range protocolNames {
+		if strings.EqualFold(name, protoName) {
+			return proto, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown protocol: %q", name)
+}
+
+func (p Protocol) String() string {
+	if name, ok := protocolNames[p]; ok {
+		return name
+	}
+	return strconv.Itoa(int(p))
+}
+
+// MarshalJSON encodes protocol by its name.
+func (p Protocol) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes protocol from API code or name.
+func (p *Protocol) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		*p = Protocol(code)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	proto, err := ParseProtocol(name)
+	if err != nil {
+		return err
+	}
+	*p = proto
+	return nil
+}
+
+// ParsePort parses single port number.
+func ParsePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || !validPort(port) {
+		return 0, fmt.Errorf("invalid port: %q", s)
+	}
+	return port, nil
+}
+
+// validPort reports whether port is in 1-65535 range.
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// ParsePorts parses port number or ports range in "from-to" format,
+// for single port both values are equal.
+func ParsePorts(s string) (from, to int, err error) {
+	parts := strings.SplitN(s, "-", 2)
+
+	from, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || !validPort(from) {
+		return 0, 0, fmt.Errorf("invalid port: %q", s)
+	}
+	to = from
+
+	if len(parts) == 2 {
+		to, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || to < from || to > 65535 {
+			return 0, 0, fmt.Errorf("invalid ports range: %q", s)
+		}
+	}
+
+	return from, to, nil
+}
+
+// PortForward is a single port forwarding rule entity.
+type PortForward struct {
+	Name     string   `json:"name"`
+	Proto    Protocol `json:"proto"`
+	Port     int      `json:"srcport"`
+	DestIP   string   `json:"destip"`
+	DestPort int      `json:"destport"`
+}
+
+// PortRangeForward is a ports range forwarding rule entity.
+type PortRangeForward struct {
+	Name     string   `json:"name"`
+	Proto    Protocol `json:"proto"`
+	FromPort int      `json:"fport"`
+	ToPort   int      `json:"tport"`
+	DestIP   string   `json:"destip"`
+}
+
+// PortForwards returns single port forwarding rules.
+func (c *Client) PortForwards() ([]PortForward, error) {
+	params := url.Values{}
+	params.Set("ftype", "1")
+
+	payload := struct {
+		List []PortForward `json:"list"`
+	}{}
+
+	if err := c.get("/api/xqnetwork/portforward", params, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.List, nil
+}
+
+// PortRangeForwards returns ports range forwarding rules.
+func (c *Client) PortRangeForwards() ([]PortRangeForward, error) {
+	params := url.Values{}
+	params.Set("ftype", "2")
+
+	payload := struct {
+		List []PortRangeForward `json:"list"`
+	}{}
+
+	if err := c.get("/api/xqnetwork/portforward", params, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.List, nil
+}
+
+// PortForwardRule is a single port or ports range forwarding rule.
+type PortForwardRule interface {
+	// redirect returns resource and params to create the rule.
+	redirect() (string, url.Values)
+	// key returns external port and protocol the rule is removed by.
+	key() (int, Protocol)
+}
+
+func (r PortForward) redirect() (string, url.Values) {
+	params := url.Values{}
+	params.Set("name", r.Name)
+	params.Set("proto", strconv.Itoa(int(r.Proto)))
+	params.Set("sport", strconv.Itoa(r.Port))
+	params.Set("ip", r.DestIP)
+	params.Set("dport", strconv.Itoa(r.DestPort))
+	return "/api/xqnetwork/add_redirect", params
+}
+
+func (r PortForward) key() (int, Protocol) {
+	return r.Port, r.Proto
+}
+
+func (r PortRangeForward) redirect() (string, url.Values) {
+	params := url.Values{}
+	params.Set("name", r.Name)
+	params.Set("proto", strconv.Itoa(int(r.Proto)))
+	params.Set("fport", strconv.Itoa(r.FromPort))
+	params.Set("tport", strconv.Itoa(r.ToPort))
+	params.Set("ip", r.DestIP)
+	return "/api/xqnetwork/add_range_redirect", params
+}
+
+func (r PortRangeForward) key() (int, Protocol) {
+	return r.FromPort, r.Proto
+}
+
+// AddPortForward creates and applies single port forwarding rule.
+func (c *Client) AddPortForward(rule PortForward) error {
+	if err := c.addRedirect(rule); err != nil {
+		return err
+	}
+	return c.applyPortForwards()
+}
+
+// AddPortRangeForward creates and applies ports range forwarding rule.
+func (c *Client) AddPortRangeForward(rule PortRangeForward) error {
+	if err := c.addRedirect(rule); err != nil {
+		return err
+	}
+	return c.applyPortForwards()
+}
+
+// UpdatePortForward replaces old forwarding rule with a new one of any
+// kind and applies rules, old rule is restored when the new one can't be
+// created.
+func (c *Client) UpdatePortForward(old, rule PortForwardRule) error {
+	if err := c.deleteRedirect(old.key()); err != nil {
+		return err
+	}
+
+	if err := c.addRedirect(rule); err != nil {
+		if restoreErr := c.addRedirect(old); restoreErr != nil {
+			return fmt.Errorf("%w, old rule isn't restored: %v", err, restoreErr)
+		}
+		return err
+	}
+
+	return c.applyPortForwards()
+}
+
+// DeletePortForward removes and applies forwarding rule of the port,
+// ranges are removed by their first port.
+func (c *Client) DeletePortForward(port int, proto Protocol) error {
+	if err := c.deleteRedirect(port, proto); err != nil {
+		return err
+	}
+	return c.applyPortForwards()
+}
+
+func (c *Client) addRedirect(rule PortForwardRule) error {
+	resource, params := rule.redirect()
+	return c.post(resource, params, nil)
+}
+
+func (c *Client) deleteRedirect(port int, proto Protocol) error {
+	params := url.Values{}
+	params.Set("port", strconv.Itoa(port))
+	params.Set("proto", strconv.Itoa(int(proto)))
+
+	return c.post("/api/xqnetwork/delete_redirect", params, nil)
+}
+
+func (c *Client) applyPortForwards() error {
+	return c.post("/api/xqnetwork/redirect_apply", nil, nil)
+}
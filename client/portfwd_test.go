@@ -0,0 +1,337 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocol_JSON(t *testing.T) {
+	var rule PortForward
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"proto": 2}`), &rule))
+	assert.Equal(t, ProtoUDP, rule.Proto)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"proto": "both"}`), &rule))
+	assert.Equal(t, ProtoBoth, rule.Proto)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"proto": "icmp"}`), &rule))
+
+	data, err := json.Marshal(PortForward{Proto: ProtoTCP})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"proto":"tcp"`)
+}
+
+func TestParsePorts(t *testing.T) {
+	from, to, err := ParsePorts("8080")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, from)
+	assert.Equal(t, 8080, to)
+
+	from, to, err = ParsePorts("8000-8010")
+	assert.NoError(t, err)
+	assert.Equal(t, 8000, from)
+	assert.Equal(t, 8010, to)
+
+	for _, s := range []string{"", "http", "0", "70000", "8010-8000", "8000-"} {
+		_, _, err = ParsePorts(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	port, err := ParsePort(" 8080 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	for _, s := range []string{"", "http", "0", "-1", "70000", "80-90"} {
+		_, err = ParsePort(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestClient_PortForwards(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/portforward")
+			// Expected query params
+			assert.Equal(t, "1", r.URL.Query().Get("ftype"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"list": [
+						{"name": "ssh", "proto": 1, "srcport": 2222, "destip": "192.168.31.2", "destport": 22}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		rules, err := c.PortForwards()
+		assert.NoError(t, err)
+		assert.Equal(t, []PortForward{
+			{Name: "ssh", Proto: ProtoTCP, Port: 2222, DestIP: "192.168.31.2", DestPort: 22},
+		}, rules)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.PortForwards()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_PortRangeForwards(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/portforward")
+		// Expected query params
+		assert.Equal(t, "2", r.URL.Query().Get("ftype"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`
+			{
+				"list": [
+					{"name": "game", "proto": 3, "fport": 27015, "tport": 27030, "destip": "192.168.31.3"}
+				],
+				"code": 0
+			}
+		`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	rules, err := c.PortRangeForwards()
+	assert.NoError(t, err)
+	assert.Equal(t, []PortRangeForward{
+		{Name: "game", Proto: ProtoBoth, FromPort: 27015, ToPort: 27030, DestIP: "192.168.31.3"},
+	}, rules)
+}
+
+func TestClient_AddPortForward(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var paths []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+
+			if r.URL.Path == "/cgi-bin/luci/;stok=token/api/xqnetwork/add_redirect" {
+				// Expected form params
+				assert.Equal(t, "ssh", r.FormValue("name"))
+				assert.Equal(t, "1", r.FormValue("proto"))
+				assert.Equal(t, "2222", r.FormValue("sport"))
+				assert.Equal(t, "192.168.31.2", r.FormValue("ip"))
+				assert.Equal(t, "22", r.FormValue("dport"))
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.AddPortForward(PortForward{
+			Name: "ssh", Proto: ProtoTCP, Port: 2222, DestIP: "192.168.31.2", DestPort: 22,
+		}))
+		assert.Equal(t, []string{
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/add_redirect",
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/redirect_apply",
+		}, paths)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		var paths []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 1537, "msg": "port conflict"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.Error(t, c.AddPortForward(PortForward{Name: "ssh", Proto: ProtoTCP, Port: 2222}))
+		assert.Equal(t, []string{"/cgi-bin/luci/;stok=token/api/xqnetwork/add_redirect"}, paths)
+	})
+}
+
+func TestClient_AddPortRangeForward(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cgi-bin/luci/;stok=token/api/xqnetwork/add_range_redirect" {
+			// Expected form params
+			assert.Equal(t, "game", r.FormValue("name"))
+			assert.Equal(t, "3", r.FormValue("proto"))
+			assert.Equal(t, "27015", r.FormValue("fport"))
+			assert.Equal(t, "27030", r.FormValue("tport"))
+			assert.Equal(t, "192.168.31.3", r.FormValue("ip"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.AddPortRangeForward(PortRangeForward{
+		Name: "game", Proto: ProtoBoth, FromPort: 27015, ToPort: 27030, DestIP: "192.168.31.3",
+	}))
+}
+
+func TestClient_DeletePortForward(t *testing.T) {
+	var paths []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		if r.URL.Path == "/cgi-bin/luci/;stok=token/api/xqnetwork/delete_redirect" {
+			// Expected form params
+			assert.Equal(t, "2222", r.FormValue("port"))
+			assert.Equal(t, "1", r.FormValue("proto"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.DeletePortForward(2222, ProtoTCP))
+	assert.Equal(t, []string{
+		"/cgi-bin/luci/;stok=token/api/xqnetwork/delete_redirect",
+		"/cgi-bin/luci/;stok=token/api/xqnetwork/redirect_apply",
+	}, paths)
+}
+
+func TestClient_UpdatePortForward(t *testing.T) {
+	old := PortForward{Name: "ssh", Proto: ProtoTCP, Port: 2222, DestIP: "192.168.31.10", DestPort: 22}
+
+	t.Run("ok", func(t *testing.T) {
+		var paths []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+
+			if r.URL.Path == "/cgi-bin/luci/;stok=token/api/xqnetwork/add_range_redirect" {
+				// Expected form params
+				assert.Equal(t, "8000", r.FormValue("fport"))
+				assert.Equal(t, "8010", r.FormValue("tport"))
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		rule := PortRangeForward{Name: "web", Proto: ProtoTCP, FromPort: 8000, ToPort: 8010, DestIP: "192.168.31.10"}
+		assert.NoError(t, c.UpdatePortForward(old, rule))
+		assert.Equal(t, []string{
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/delete_redirect",
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/add_range_redirect",
+			"/cgi-bin/luci/;stok=token/api/xqnetwork/redirect_apply",
+		}, paths)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		var restored PortForward
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+
+			switch {
+			case r.URL.Path != "/cgi-bin/luci/;stok=token/api/xqnetwork/add_redirect":
+				w.Write([]byte(`{"code": 0}`))
+			case r.FormValue("sport") == "2223":
+				w.Write([]byte(`{"code": 1537, "msg": "port conflict"}`))
+			default:
+				restored.Name = r.FormValue("name")
+				restored.DestPort, _ = strconv.Atoi(r.FormValue("dport"))
+				w.Write([]byte(`{"code": 0}`))
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		rule := old
+		rule.Port = 2223
+		assert.Error(t, c.UpdatePortForward(old, rule))
+		assert.Equal(t, "ssh", restored.Name)
+		assert.Equal(t, 22, restored.DestPort)
+	})
+}
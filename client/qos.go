@@ -0,0 +1,79 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// QoS is a QoS (smart bandwidth control) status entity.
+type QoS struct {
+	Status  QoSStatus   `json:"status"`
+	Band    QoSBand     `json:"band"`
+	Devices []DeviceQoS `json:"list"`
+}
+
+// QoSStatus is a QoS service status entity.
+type QoSStatus struct {
+	On   int `json:"on"`
+	Mode int `json:"mode"`
+}
+
+// QoSBand is a QoS total bandwidth entity, values are in Mbit/s.
+type QoSBand struct {
+	Download float64 `json:"download"`
+	Upload   float64 `json:"upload"`
+}
+
+// DeviceQoS is a connected device QoS entity.
+type DeviceQoS struct {
+	Mac   string   `json:"mac"`
+	IP    string   `json:"ip"`
+	Name  string   `json:"name"`
+	Limit QoSLimit `json:"qos"`
+}
+
+// QoSLimit is a device speed limit entity, values are in KB/s
+// and zero value means no limit.
+type QoSLimit struct {
+	MaxUpload   uint64 `json:"upmax"`
+	MaxDownload uint64 `json:"downmax"`
+}
+
+// Limit returns device speed limit by MAC address.
+func (q QoS) Limit(mac string) (QoSLimit, bool) {
+	for _, device := range q.Devices {
+		if device.Mac == mac {
+			return device.Limit, true
+		}
+	}
+	return QoSLimit{}, false
+}
+
+// QoSInfo returns QoS mode and per-device speed limits.
+func (c *Client) QoSInfo() (QoS, error) {
+	var qos QoS
+
+	if err := c.get("/api/misystem/qos_info", nil, &qos); err != nil {
+		return qos, err
+	}
+
+	return qos, nil
+}
+
+// SetQoSLimit sets device upload and download speed limits in KB/s.
+func (c *Client) SetQoSLimit(mac string, upload, download uint64) error {
+	params := url.Values{}
+	params.Set("mac", mac)
+	params.Set("upload", strconv.FormatUint(upload, 10))
+	params.Set("download", strconv.FormatUint(download, 10))
+
+	return c.post("/api/misystem/qos_limit", params, nil)
+}
+
+// ClearQoSLimit removes device speed limits.
+func (c *Client) ClearQoSLimit(mac string) error {
+	params := url.Values{}
+	params.Set("mac", mac)
+
+	return c.post("/api/misystem/qos_offlimit", params, nil)
+}
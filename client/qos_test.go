@@ -0,0 +1,165 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_QoSInfo(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/qos_info")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"status": {"on": 1, "mode": 2},
+					"band": {"download": 100, "upload": 20},
+					"list": [
+						{
+							"mac": "00:11:22:33:44:55",
+							"ip": "192.168.31.10",
+							"name": "client_1",
+							"qos": {"upmax": 128, "downmax": 1024}
+						}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		qos, err := c.QoSInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, QoS{
+			Status: QoSStatus{On: 1, Mode: 2},
+			Band:   QoSBand{Download: 100, Upload: 20},
+			Devices: []DeviceQoS{
+				{
+					Mac:   "00:11:22:33:44:55",
+					IP:    "192.168.31.10",
+					Name:  "client_1",
+					Limit: QoSLimit{MaxUpload: 128, MaxDownload: 1024},
+				},
+			},
+		}, qos)
+
+		limit, ok := qos.Limit("00:11:22:33:44:55")
+		assert.True(t, ok)
+		assert.Equal(t, QoSLimit{MaxUpload: 128, MaxDownload: 1024}, limit)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 1523, "msg": "qos is not supported"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.QoSInfo()
+		assert.Equal(t, &APIError{Code: 1523, Message: "qos is not supported"}, err)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.QoSInfo()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetQoSLimit(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected method
+			assert.Equal(t, "POST", r.Method)
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/qos_limit")
+			// Expected form params
+			assert.Equal(t, "00:11:22:33:44:55", r.FormValue("mac"))
+			assert.Equal(t, "128", r.FormValue("upload"))
+			assert.Equal(t, "1024", r.FormValue("download"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.SetQoSLimit("00:11:22:33:44:55", 128, 1024))
+	})
+
+	t.Run("not authorized", func(t *testing.T) {
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       "localhost",
+			nonce:      "nonce",
+		}
+
+		assert.Error(t, c.SetQoSLimit("00:11:22:33:44:55", 128, 1024), "client is not authorized")
+	})
+}
+
+func TestClient_ClearQoSLimit(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected method
+			assert.Equal(t, "POST", r.Method)
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/qos_offlimit")
+			// Expected form params
+			assert.Equal(t, "00:11:22:33:44:55", r.FormValue("mac"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.ClearQoSLimit("00:11:22:33:44:55"))
+	})
+}
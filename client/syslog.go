@@ -0,0 +1,57 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LogEntry is a router system log record entity.
+type LogEntry struct {
+	Time     string `json:"time"`
+	Facility string `json:"facility"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// syslogLine matches "Mon May  4 10:00:00 2020 daemon.err pppd[123]: message" lines.
+var syslogLine = regexp.MustCompile(`^(\w{3} \w{3} [ \d]\d \d\d:\d\d:\d\d \d{4}) (\w+)\.(\w+) (.*)$`)
+
+// ParseLog parses raw system log, lines in unknown format are kept
+// as messages without time and severity.
+func ParseLog(raw string) []LogEntry {
+	var entries []LogEntry
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		m := syslogLine.FindStringSubmatch(line)
+		if m == nil {
+			entries = append(entries, LogEntry{Message: line})
+			continue
+		}
+		entries = append(entries, LogEntry{
+			Time:     m[1],
+			Facility: m[2],
+			Severity: m[3],
+			Message:  m[4],
+		})
+	}
+
+	return entries
+}
+
+// SystemLog returns router system log records from oldest to newest.
+func (c *Client) SystemLog() ([]LogEntry, error) {
+	payload := struct {
+		Log string `json:"log"`
+	}{}
+
+	if err := c.get("/api/misystem/sys_log", nil, &payload); err != nil {
+		return nil, err
+	}
+
+	return ParseLog(payload.Log), nil
+}
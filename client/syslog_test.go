@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLog(t *testing.T) {
+	entries := ParseLog("Mon May  4 10:00:00 2020 daemon.err pppd[123]: LCP terminated\n" +
+		"\n" +
+		"garbage line\r\n" +
+		"Mon May  4 10:00:05 2020 kern.info kernel: eth0.2 link up\n")
+
+	assert.Equal(t, []LogEntry{
+		{Time: "Mon May  4 10:00:00 2020", Facility: "daemon", Severity: "err", Message: "pppd[123]: LCP terminated"},
+		{Message: "garbage line"},
+		{Time: "Mon May  4 10:00:05 2020", Facility: "kern", Severity: "info", Message: "kernel: eth0.2 link up"},
+	}, entries)
+}
+
+func TestClient_SystemLog(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/sys_log")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"log": "Mon May  4 10:00:00 2020 daemon.warn dnsmasq[1]: query timeout\n", "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		entries, err := c.SystemLog()
+		assert.NoError(t, err)
+		assert.Equal(t, []LogEntry{
+			{Time: "Mon May  4 10:00:00 2020", Facility: "daemon", Severity: "warn", Message: "dnsmasq[1]: query timeout"},
+		}, entries)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.SystemLog()
+		assert.Error(t, err)
+	})
+}
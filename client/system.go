@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemTime is a router clock and time settings entity.
+type SystemTime struct {
+	Time       time.Time `json:"time"`
+	Timezone   string    `json:"timezone"`
+	NTPServers []string  `json:"ntp"`
+	// Local is a local machine time when router time is received.
+	Local time.Time `json:"local"`
+}
+
+// Skew returns router clock offset from the local machine clock,
+// positive skew means router clock is ahead.
+func (t SystemTime) Skew() time.Duration {
+	return t.Time.Sub(t.Local)
+}
+
+// SystemTime returns router clock and time settings.
+func (c *Client) SystemTime() (SystemTime, error) {
+	payload := struct {
+		Time struct {
+			Timestamp int64  `json:"timestamp"`
+			Timezone  string `json:"timezone"`
+		} `json:"time"`
+		NTP string `json:"ntp"`
+	}{}
+
+	if err := c.get("/api/misystem/sys_time", nil, &payload); err != nil {
+		return SystemTime{}, err
+	}
+
+	t := SystemTime{
+		Timezone: payload.Time.Timezone,
+		Local:    time.Now(),
+	}
+	// zero time is left when router doesn't report its clock
+	if payload.Time.Timestamp != 0 {
+		t.Time = time.Unix(payload.Time.Timestamp, 0)
+	}
+	for _, server := range strings.Split(payload.NTP, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			t.NTPServers = append(t.NTPServers, server)
+		}
+	}
+
+	return t, nil
+}
+
+// SetSystemTime sets router clock.
+func (c *Client) SetSystemTime(t time.Time) error {
+	params := url.Values{}
+	params.Set("timestamp", strconv.FormatInt(t.Unix(), 10))
+
+	return c.post("/api/misystem/set_sys_time", params, nil)
+}
+
+// SetTimezone sets router timezone, e.g. "Europe/Berlin".
+func (c *Client) SetTimezone(timezone string) error {
+	if timezone == "" {
+		return errors.New("timezone is required")
+	}
+
+	params := url.Values{}
+	params.Set("timezone", timezone)
+
+	return c.post("/api/misystem/set_timezone", params, nil)
+}
+
+// SetNTPServers replaces router NTP servers list.
+func (c *Client) SetNTPServers(servers []string) error {
+	params := url.Values{}
+	params.Set("ntp", strings.Join(servers, ","))
+
+	return c.post("/api/misystem/set_ntp", params, nil)
+}
+
+// RouterName returns router name.
+func (c *Client) RouterName() (string, error) {
+	payload := struct {
+		Name string `json:"name"`
+	}{}
+
+	if err := c.get("/api/misystem/router_name", nil, &payload); err != nil {
+		return "", err
+	}
+
+	return payload.Name, nil
+}
+
+// SetRouterName sets router name.
+func (c *Client) SetRouterName(name string) error {
+	if name == "" {
+		return errors.New("router name is required")
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+
+	return c.post("/api/misystem/set_router_name", params, nil)
+}
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SystemTime(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/sys_time")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"time": {"timestamp": 1760781600, "timezone": "Europe/Berlin"},
+					"ntp": "pool.ntp.org, time.example.com",
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		st, err := c.SystemTime()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1760781600), st.Time.Unix())
+		assert.Equal(t, "Europe/Berlin", st.Timezone)
+		assert.Equal(t, []string{"pool.ntp.org", "time.example.com"}, st.NTPServers)
+		assert.WithinDuration(t, time.Now(), st.Local, time.Minute)
+	})
+
+	t.Run("no timestamp", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"time": {"timezone": "Europe/Berlin"}, "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		st, err := c.SystemTime()
+		assert.NoError(t, err)
+		assert.True(t, st.Time.IsZero())
+		assert.Equal(t, "Europe/Berlin", st.Timezone)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.SystemTime()
+		assert.Error(t, err)
+	})
+}
+
+func TestSystemTime_Skew(t *testing.T) {
+	now := time.Now()
+	st := SystemTime{Time: now.Add(90 * time.Second), Local: now}
+	assert.Equal(t, 90*time.Second, st.Skew())
+}
+
+func TestClient_SetSystemTime(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/set_sys_time")
+		// Expected form params
+		assert.Equal(t, "1760781600", r.FormValue("timestamp"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetSystemTime(time.Unix(1760781600, 0)))
+}
+
+func TestClient_SetTimezone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/set_timezone")
+		// Expected form params
+		assert.Equal(t, "Asia/Shanghai", r.FormValue("timezone"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetTimezone("Asia/Shanghai"))
+	assert.Error(t, c.SetTimezone(""))
+}
+
+func TestClient_SetNTPServers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/set_ntp")
+		// Expected form params
+		assert.Equal(t, "pool.ntp.org,time.example.com", r.FormValue("ntp"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetNTPServers([]string{"pool.ntp.org", "time.example.com"}))
+}
+
+func TestClient_RouterName(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/router_name")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"name": "Xiaomi_1234", "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		name, err := c.RouterName()
+		assert.NoError(t, err)
+		assert.Equal(t, "Xiaomi_1234", name)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 1523, "msg": "internal error"}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.RouterName()
+		assert.IsType(t, &APIError{}, err)
+	})
+}
+
+func TestClient_SetRouterName(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/set_router_name")
+		// Expected form params
+		assert.Equal(t, "office", r.FormValue("name"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetRouterName("office"))
+}
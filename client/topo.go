@@ -0,0 +1,42 @@
+package client
+
+// TopoNode is a mesh network node entity, the root node is a main router
+// and leafs are satellites connected to it.
+type TopoNode struct {
+	Name     string       `json:"name"`
+	Hardware string       `json:"hardware"`
+	Mac      string       `json:"mac"`
+	IP       string       `json:"ip"`
+	Backhaul string       `json:"backhaul"`
+	Clients  []TopoClient `json:"clients"`
+	Leafs    []TopoNode   `json:"leafs"`
+}
+
+// TopoClient is a client device attached to a mesh node.
+type TopoClient struct {
+	Mac  string `json:"mac"`
+	Name string `json:"name"`
+	IP   string `json:"ip"`
+}
+
+// ClientCount returns number of clients attached to the node and its leafs.
+func (n TopoNode) ClientCount() int {
+	count := len(n.Clients)
+	for _, leaf := range n.Leafs {
+		count += leaf.ClientCount()
+	}
+	return count
+}
+
+// Topology returns mesh network topology graph.
+func (c *Client) Topology() (TopoNode, error) {
+	payload := struct {
+		Graph TopoNode `json:"graph"`
+	}{}
+
+	if err := c.get("/api/misystem/topo_graph", nil, &payload); err != nil {
+		return TopoNode{}, err
+	}
+
+	return payload.Graph, nil
+}
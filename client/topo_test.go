@@ -0,0 +1,90 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Topology(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/misystem/topo_graph")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"graph": {
+						"name": "Router",
+						"hardware": "RA67",
+						"mac": "AA:BB:CC:DD:EE:FF",
+						"ip": "192.168.31.1",
+						"clients": [{"mac": "00:11:22:33:44:55", "name": "laptop", "ip": "192.168.31.10"}],
+						"leafs": [
+							{
+								"name": "Bedroom",
+								"hardware": "RA67",
+								"mac": "AA:BB:CC:DD:EE:00",
+								"ip": "192.168.31.2",
+								"backhaul": "5G",
+								"clients": [{"mac": "55:44:33:22:11:00", "name": "phone", "ip": "192.168.31.11"}]
+							}
+						]
+					},
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		topo, err := c.Topology()
+		assert.NoError(t, err)
+		assert.Equal(t, TopoNode{
+			Name:     "Router",
+			Hardware: "RA67",
+			Mac:      "AA:BB:CC:DD:EE:FF",
+			IP:       "192.168.31.1",
+			Clients:  []TopoClient{{Mac: "00:11:22:33:44:55", Name: "laptop", IP: "192.168.31.10"}},
+			Leafs: []TopoNode{
+				{
+					Name:     "Bedroom",
+					Hardware: "RA67",
+					Mac:      "AA:BB:CC:DD:EE:00",
+					IP:       "192.168.31.2",
+					Backhaul: "5G",
+					Clients:  []TopoClient{{Mac: "55:44:33:22:11:00", Name: "phone", IP: "192.168.31.11"}},
+				},
+			},
+		}, topo)
+		assert.Equal(t, 2, topo.ClientCount())
+		assert.Equal(t, 1, topo.Leafs[0].ClientCount())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.Topology()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "transport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(opts TransportOptions) error {
+		transport, err := NewTransport(opts)
+		if err != nil {
+			return err
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(ts.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	t.Run("ca bundle", func(t *testing.T) {
+		assert.NoError(t, get(TransportOptions{CAFile: caFile}))
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		assert.NoError(t, get(TransportOptions{InsecureSkipVerify: true}))
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		assert.Error(t, get(TransportOptions{}))
+	})
+
+	t.Run("invalid ca bundle", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{CAFile: filepath.Join(dir, "missing.pem")})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid proxy scheme", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{Proxy: "ftp://127.0.0.1:21"})
+		assert.Error(t, err)
+	})
+
+	t.Run("key without certificate", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{KeyFile: caFile})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected absolute request URI
+		assert.Equal(t, "http://192.168.31.1/cgi-bin/luci/web", r.RequestURI)
+
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(TransportOptions{Proxy: proxy.URL})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://192.168.31.1/cgi-bin/luci/web")
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	}
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshTimeout is a timeout of SSH connection establishment.
+const sshTimeout = 15 * time.Second
+
+// SSHOptions are options of SSH tunnel to the router through a jump host.
+type SSHOptions struct {
+	// Address is a jump host address, port defaults to 22.
+	Address string `json:"address"`
+	User    string `json:"user"`
+	// KeyFile is an unencrypted private key, SSH agent is used when it's
+	// empty or the key is rejected.
+	KeyFile string `json:"key,omitempty"`
+	// KnownHostsFile defaults to ~/.ssh/known_hosts.
+	KnownHostsFile        string `json:"known_hosts,omitempty"`
+	InsecureIgnoreHostKey bool   `json:"insecure,omitempty"`
+}
+
+// sshDialer dials connections through SSH tunnel, connection to the jump
+// host is established on first dial and re-established when it's broken.
+type sshDialer struct {
+	address string
+	config  *ssh.ClientConfig
+	// agentSock is an SSH agent socket dialed for each handshake
+	agentSock string
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func newSSHDialer(opts SSHOptions) (*sshDialer, error) {
+	if opts.Address == "" || opts.User == "" {
+		return nil, errors.New("ssh address and user are required")
+	}
+
+	address := opts.Address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "22")
+	}
+
+	auth, agentSock, err := opts.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := opts.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sshDialer{
+		address:   address,
+		agentSock: agentSock,
+		config: &ssh.ClientConfig{
+			User:            opts.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sshTimeout,
+		},
+	}, nil
+}
+
+// auth returns key file auth methods and SSH agent socket, agent is dialed
+// on each handshake since its connection can't outlive agent restarts.
+func (o SSHOptions) auth() ([]ssh.AuthMethod, string, error) {
+	var methods []ssh.AuthMethod
+
+	if o.KeyFile != "" {
+		pem, err := ioutil.ReadFile(o.KeyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("can't read ssh key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return nil, "", fmt.Errorf("can't parse ssh key, use ssh agent for encrypted keys: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock != "" && len(methods) == 0 {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, "", fmt.Errorf("can't connect to ssh agent: %w", err)
+		}
+		conn.Close()
+	}
+
+	if len(methods) == 0 && sock == "" {
+		return nil, "", errors.New("ssh key is not set and ssh agent is not running")
+	}
+	return methods, sock, nil
+}
+
+func (o SSHOptions) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if o.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	file := o.KnownHostsFile
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't read known hosts: %w", err)
+	}
+	return callback, nil
+}
+
+// DialContext connects to the address from the jump host.
+func (d *sshDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.Dial(network, addr)
+	if err == nil {
+		return conn, nil
+	}
+
+	// remote dial errors are returned as is while the jump host replies,
+	// connection is re-established only when it's broken
+	if _, _, kaErr := client.SendRequest("keepalive@openssh.com", true, nil); kaErr == nil {
+		return nil, err
+	}
+	d.reset(client)
+	client, err = d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Dial(network, addr)
+}
+
+func (d *sshDialer) connect(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	config := *d.config
+	if d.agentSock != "" {
+		agentConn, err := net.Dial("unix", d.agentSock)
+		if err != nil && len(config.Auth) == 0 {
+			return nil, fmt.Errorf("can't connect to ssh agent: %w", err)
+		}
+		if err == nil {
+			defer agentConn.Close()
+			config.Auth = append(config.Auth[:len(config.Auth):len(config.Auth)],
+				ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+
+	dialer := net.Dialer{Timeout: sshTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", d.address)
+	if err != nil {
+		return nil, fmt.Errorf("ssh dial error: %w", err)
+	}
+
+	// handshake is limited by the timeout and the context deadline
+	deadline := time.Now().Add(sshTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, d.address, &config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake error: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	d.client = ssh.NewClient(c, chans, reqs)
+	return d.client, nil
+}
+
+func (d *sshDialer) reset(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client == client {
+		d.client.Close()
+		d.client = nil
+	}
+}
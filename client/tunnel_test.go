@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startSSHServer starts SSH server which accepts the client key and
+// forwards direct-tcpip channels.
+func startSSHServer(t *testing.T, hostKey ssh.Signer, clientKey ssh.PublicKey) net.Listener {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "user" && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown public key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+
+				for newCh := range chans {
+					var target struct {
+						Host     string
+						Port     uint32
+						OrigHost string
+						OrigPort uint32
+					}
+					if newCh.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newCh.ExtraData(), &target) != nil {
+						newCh.Reject(ssh.UnknownChannelType, "unsupported channel")
+						continue
+					}
+
+					targetConn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+					if err != nil {
+						newCh.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					ch, chReqs, err := newCh.Accept()
+					if err != nil {
+						targetConn.Close()
+						continue
+					}
+					go ssh.DiscardRequests(chReqs)
+					go func() {
+						io.Copy(ch, targetConn)
+						ch.Close()
+					}()
+					go func() {
+						io.Copy(targetConn, ch)
+						targetConn.Close()
+					}()
+				}
+			}()
+		}
+	}()
+
+	return listener
+}
+
+func TestNewTransport_SSH(t *testing.T) {
+	os.Unsetenv("SSH_AUTH_SOCK")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "tunnel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	newKey := func() (*rsa.PrivateKey, ssh.Signer) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := ssh.NewSignerFromKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key, signer
+	}
+
+	_, hostSigner := newKey()
+	clientKey, clientSigner := newKey()
+
+	keyFile := filepath.Join(dir, "id_rsa")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(clientKey)})
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := startSSHServer(t, hostSigner, clientSigner.PublicKey())
+	defer listener.Close()
+	address := listener.Addr().String()
+
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, hostSigner.PublicKey())
+	if err := ioutil.WriteFile(knownHostsFile, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(opts SSHOptions) error {
+		transport, err := NewTransport(TransportOptions{SSH: &opts})
+		if err != nil {
+			return err
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(ts.URL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		return nil
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		assert.NoError(t, get(SSHOptions{
+			Address:        address,
+			User:           "user",
+			KeyFile:        keyFile,
+			KnownHostsFile: knownHostsFile,
+		}))
+	})
+
+	t.Run("remote dial error", func(t *testing.T) {
+		d, err := newSSHDialer(SSHOptions{
+			Address:        address,
+			User:           "user",
+			KeyFile:        keyFile,
+			KnownHostsFile: knownHostsFile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		closed.Close()
+
+		conn, err := d.DialContext(context.Background(), "tcp", ts.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+		client := d.client
+
+		// Expected the jump host connection to be kept
+		_, err = d.DialContext(context.Background(), "tcp", closed.Addr().String())
+		assert.Error(t, err)
+		assert.True(t, client == d.client)
+		d.reset(client)
+	})
+
+	t.Run("unknown host key", func(t *testing.T) {
+		emptyFile := filepath.Join(dir, "empty")
+		if err := ioutil.WriteFile(emptyFile, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, get(SSHOptions{
+			Address:        address,
+			User:           "user",
+			KeyFile:        keyFile,
+			KnownHostsFile: emptyFile,
+		}))
+	})
+
+	t.Run("rejected user", func(t *testing.T) {
+		assert.Error(t, get(SSHOptions{
+			Address:               address,
+			User:                  "root",
+			KeyFile:               keyFile,
+			InsecureIgnoreHostKey: true,
+		}))
+	})
+
+	t.Run("no auth", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{SSH: &SSHOptions{Address: address, User: "user"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("proxy and ssh", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{
+			Proxy: "socks5://127.0.0.1:1080",
+			SSH:   &SSHOptions{Address: address, User: "user", KeyFile: keyFile},
+		})
+		assert.Error(t, err)
+	})
+}
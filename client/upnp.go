@@ -0,0 +1,45 @@
+package client
+
+import "net/url"
+
+// UPnP is a UPnP service status entity.
+type UPnP struct {
+	Status   int           `json:"status"`
+	Mappings []UPnPMapping `json:"list"`
+}
+
+// Enabled reports whether UPnP service is on.
+func (u UPnP) Enabled() bool {
+	return u.Status == 1
+}
+
+// UPnPMapping is a port mapping opened by a LAN device over UPnP.
+type UPnPMapping struct {
+	Protocol     string `json:"protocol"`
+	ExternalPort int    `json:"rport"`
+	InternalIP   string `json:"ip"`
+	InternalPort int    `json:"cport"`
+	Description  string `json:"name"`
+}
+
+// UPnP returns UPnP service status and port mappings.
+func (c *Client) UPnP() (UPnP, error) {
+	var upnp UPnP
+
+	if err := c.get("/api/xqsystem/upnp", nil, &upnp); err != nil {
+		return upnp, err
+	}
+
+	return upnp, nil
+}
+
+// SetUPnP turns UPnP service on or off.
+func (c *Client) SetUPnP(enabled bool) error {
+	params := url.Values{}
+	params.Set("switch", "0")
+	if enabled {
+		params.Set("switch", "1")
+	}
+
+	return c.post("/api/xqsystem/upnp_switch", params, nil)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_UPnP(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/upnp")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"status": 1,
+					"list": [
+						{"protocol": "UDP", "rport": 3074, "ip": "192.168.31.20", "cport": 3074, "name": "Xbox"}
+					],
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		upnp, err := c.UPnP()
+		assert.NoError(t, err)
+		assert.True(t, upnp.Enabled())
+		assert.Equal(t, []UPnPMapping{
+			{Protocol: "UDP", ExternalPort: 3074, InternalIP: "192.168.31.20", InternalPort: 3074, Description: "Xbox"},
+		}, upnp.Mappings)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.UPnP()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetUPnP(t *testing.T) {
+	for _, tc := range []struct {
+		enabled bool
+		value   string
+	}{
+		{true, "1"},
+		{false, "0"},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/upnp_switch")
+			// Expected form params
+			assert.Equal(t, tc.value, r.FormValue("switch"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"code": 0}`))
+		}))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		assert.NoError(t, c.SetUPnP(tc.enabled))
+		ts.Close()
+	}
+}
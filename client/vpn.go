@@ -0,0 +1,121 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// VPN client connection statuses.
+const (
+	VPNDisconnected = 0
+	VPNConnecting   = 1
+	VPNConnected    = 2
+)
+
+// VPNProfile is a VPN client connection profile entity.
+type VPNProfile struct {
+	ID       string `json:"id"`
+	Name     string `json:"oname"`
+	Proto    string `json:"proto"`
+	Server   string `json:"server"`
+	Username string `json:"username"`
+}
+
+// VPNStatus is a VPN client connection state entity.
+type VPNStatus struct {
+	Status    int           `json:"status"`
+	ProfileID string        `json:"id"`
+	IP        string        `json:"ip"`
+	UpTime    time.Duration `json:"uptime"`
+	// AllTraffic reports whether all traffic is routed through the tunnel,
+	// otherwise only VPN subnets are.
+	AllTraffic bool `json:"all_traffic"`
+}
+
+func (s VPNStatus) String() string {
+	switch s.Status {
+	case VPNDisconnected:
+		return "disconnected"
+	case VPNConnecting:
+		return "connecting"
+	case VPNConnected:
+		return "connected"
+	default:
+		return "unknown"
+	}
+}
+
+// VPN is a VPN client profiles and connection state entity.
+type VPN struct {
+	Profiles []VPNProfile `json:"profiles"`
+	Status   VPNStatus    `json:"status"`
+}
+
+// Profile returns profile by ID.
+func (v VPN) Profile(id string) (VPNProfile, bool) {
+	for _, p := range v.Profiles {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return VPNProfile{}, false
+}
+
+// vpnStatusPayload is a raw VPN status API response.
+type vpnStatusPayload struct {
+	Status       int             `json:"status"`
+	ID           string          `json:"id"`
+	IP           string          `json:"ip"`
+	UpTime       json.RawMessage `json:"uptime"`
+	DefaultRoute int             `json:"defaultroute"`
+}
+
+// VPN returns VPN client profiles and connection state.
+func (c *Client) VPN() (VPN, error) {
+	var vpn VPN
+
+	list := struct {
+		List []VPNProfile `json:"list"`
+	}{}
+	if err := c.get("/api/xqsystem/vpn_list", nil, &list); err != nil {
+		return vpn, err
+	}
+	vpn.Profiles = list.List
+
+	var status vpnStatusPayload
+	if err := c.get("/api/xqsystem/vpn_status", nil, &status); err != nil {
+		return vpn, err
+	}
+	vpn.Status = VPNStatus{
+		Status:     status.Status,
+		ProfileID:  status.ID,
+		IP:         status.IP,
+		UpTime:     parseSeconds(status.UpTime),
+		AllTraffic: status.DefaultRoute == 1,
+	}
+
+	return vpn, nil
+}
+
+// ConnectVPN connects VPN client using the profile.
+func (c *Client) ConnectVPN(id string) error {
+	if id == "" {
+		return errors.New("VPN profile id is required")
+	}
+
+	params := url.Values{}
+	params.Set("conn", "1")
+	params.Set("id", id)
+
+	return c.post("/api/xqsystem/vpn_switch", params, nil)
+}
+
+// DisconnectVPN disconnects VPN client.
+func (c *Client) DisconnectVPN() error {
+	params := url.Values{}
+	params.Set("conn", "0")
+
+	return c.post("/api/xqsystem/vpn_switch", params, nil)
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_VPN(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+
+			switch r.URL.Path {
+			case "/cgi-bin/luci/;stok=token/api/xqsystem/vpn_list":
+				w.Write([]byte(`
+					{
+						"list": [
+							{"id": "office", "oname": "Office", "proto": "l2tp", "server": "vpn.example.com", "username": "branch"}
+						],
+						"code": 0
+					}
+				`))
+			case "/cgi-bin/luci/;stok=token/api/xqsystem/vpn_status":
+				w.Write([]byte(`{"status": 2, "id": "office", "ip": "10.8.0.2", "uptime": "3600", "defaultroute": 1, "code": 0}`))
+			default:
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		vpn, err := c.VPN()
+		assert.NoError(t, err)
+		assert.Equal(t, VPN{
+			Profiles: []VPNProfile{
+				{ID: "office", Name: "Office", Proto: "l2tp", Server: "vpn.example.com", Username: "branch"},
+			},
+			Status: VPNStatus{
+				Status:     VPNConnected,
+				ProfileID:  "office",
+				IP:         "10.8.0.2",
+				UpTime:     time.Hour,
+				AllTraffic: true,
+			},
+		}, vpn)
+		assert.Equal(t, "connected", vpn.Status.String())
+
+		profile, ok := vpn.Profile("office")
+		assert.True(t, ok)
+		assert.Equal(t, "Office", profile.Name)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.VPN()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_ConnectVPN(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/vpn_switch")
+		// Expected form params
+		assert.Equal(t, "1", r.FormValue("conn"))
+		assert.Equal(t, "office", r.FormValue("id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.ConnectVPN("office"))
+	assert.Error(t, c.ConnectVPN(""))
+}
+
+func TestClient_DisconnectVPN(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqsystem/vpn_switch")
+		// Expected form params
+		assert.Equal(t, "0", r.FormValue("conn"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.DisconnectVPN())
+}
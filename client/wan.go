@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WANInfo is a WAN connection details entity.
+type WANInfo struct {
+	Mac     string        `json:"mac"`
+	Type    string        `json:"type"`
+	IP      string        `json:"ip"`
+	Mask    string        `json:"mask"`
+	Gateway string        `json:"gateway"`
+	DNS     []string      `json:"dns"`
+	Link    bool          `json:"link"`
+	Up      bool          `json:"up"`
+	UpTime  time.Duration `json:"uptime"`
+}
+
+// wanInfoPayload is a raw WAN info API response.
+type wanInfoPayload struct {
+	Info struct {
+		Mac     string `json:"mac"`
+		Details struct {
+			WANType string `json:"wanType"`
+		} `json:"details"`
+		IPv4 []struct {
+			IP   string `json:"ip"`
+			Mask string `json:"mask"`
+		} `json:"ipv4"`
+		Gateway string          `json:"gateWay"`
+		DNS     string          `json:"dnsAddrs"`
+		DNS1    string          `json:"dnsAddrs1"`
+		Status  int             `json:"status"`
+		Link    int             `json:"link"`
+		UpTime  json.RawMessage `json:"uptime"`
+	} `json:"info"`
+}
+
+// WANInfo returns WAN connection details.
+func (c *Client) WANInfo() (WANInfo, error) {
+	var payload wanInfoPayload
+
+	if err := c.get("/api/xqnetwork/wan_info", nil, &payload); err != nil {
+		return WANInfo{}, err
+	}
+
+	info := WANInfo{
+		Mac:     payload.Info.Mac,
+		Type:    payload.Info.Details.WANType,
+		Gateway: payload.Info.Gateway,
+		Link:    payload.Info.Link == 1,
+		Up:      payload.Info.Status == 1,
+		UpTime:  parseSeconds(payload.Info.UpTime),
+	}
+	if len(payload.Info.IPv4) > 0 {
+		info.IP = payload.Info.IPv4[0].IP
+		info.Mask = payload.Info.IPv4[0].Mask
+	}
+	for _, dns := range []string{payload.Info.DNS, payload.Info.DNS1} {
+		if dns != "" {
+			info.DNS = append(info.DNS, dns)
+		}
+	}
+
+	return info, nil
+}
+
+// parseSeconds parses duration in seconds encoded as JSON number or string.
+func parseSeconds(data json.RawMessage) time.Duration {
+	return time.Duration(parseNumber(data) * float64(time.Second))
+}
+
+// parseNumber parses number encoded as JSON number or string,
+// invalid values are parsed as zero.
+func parseNumber(data json.RawMessage) float64 {
+	var s json.Number
+	if err := json.Unmarshal(data, &s); err != nil {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return 0
+		}
+		s = json.Number(str)
+	}
+
+	n, err := s.Float64()
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_WANInfo(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/wan_info")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"info": {
+						"mac": "AA:BB:CC:DD:EE:F0",
+						"mtu": "1480",
+						"details": {"wanType": "pppoe", "username": "user"},
+						"ipv4": [{"ip": "203.0.113.7", "mask": "255.255.255.255"}],
+						"gateWay": "203.0.113.1",
+						"dnsAddrs": "8.8.8.8",
+						"dnsAddrs1": "1.1.1.1",
+						"status": 1,
+						"link": 1,
+						"uptime": "3600.5"
+					},
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		info, err := c.WANInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, WANInfo{
+			Mac:     "AA:BB:CC:DD:EE:F0",
+			Type:    "pppoe",
+			IP:      "203.0.113.7",
+			Mask:    "255.255.255.255",
+			Gateway: "203.0.113.1",
+			DNS:     []string{"8.8.8.8", "1.1.1.1"},
+			Link:    true,
+			Up:      true,
+			UpTime:  3600*time.Second + 500*time.Millisecond,
+		}, info)
+	})
+
+	t.Run("disconnected", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`{"info": {"details": {"wanType": "dhcp"}, "status": 0, "link": 0, "uptime": 0}, "code": 0}`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		info, err := c.WANInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, WANInfo{Type: "dhcp"}, info)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.WANInfo()
+		assert.Error(t, err)
+	})
+}
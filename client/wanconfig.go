@@ -0,0 +1,180 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// WAN connection modes.
+const (
+	WANModeDHCP   = "dhcp"
+	WANModePPPoE  = "pppoe"
+	WANModeStatic = "static"
+)
+
+// WANConfig is a WAN connection configuration entity, zero MTU and
+// empty DNS keep router defaults.
+type WANConfig struct {
+	Mode     string   `json:"mode"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"-"`
+	IP       string   `json:"ip,omitempty"`
+	Mask     string   `json:"mask,omitempty"`
+	Gateway  string   `json:"gateway,omitempty"`
+	DNS      []string `json:"dns,omitempty"`
+	MTU      int      `json:"mtu,omitempty"`
+	Mac      string   `json:"mac"`
+}
+
+// Validate checks that config has all fields required by its mode.
+func (cfg WANConfig) Validate() error {
+	switch cfg.Mode {
+	case WANModeDHCP:
+	case WANModePPPoE:
+		if cfg.Username == "" || cfg.Password == "" {
+			return errors.New("PPPoE username and password are required")
+		}
+	case WANModeStatic:
+		ip := net.ParseIP(cfg.IP).To4()
+		if ip == nil {
+			return fmt.Errorf("invalid WAN IP: %q", cfg.IP)
+		}
+		mask, err := parseMask(cfg.Mask)
+		if err != nil {
+			return err
+		}
+		gateway := net.ParseIP(cfg.Gateway).To4()
+		if gateway == nil {
+			return fmt.Errorf("invalid gateway: %q", cfg.Gateway)
+		}
+		subnet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		if !subnet.Contains(gateway) {
+			return fmt.Errorf("gateway %s is outside of %s", cfg.Gateway, subnet.String())
+		}
+		if len(cfg.DNS) == 0 {
+			return errors.New("DNS servers are required in static mode")
+		}
+	default:
+		return fmt.Errorf("unknown WAN mode: %q", cfg.Mode)
+	}
+
+	if len(cfg.DNS) > 2 {
+		return errors.New("at most 2 DNS servers are supported")
+	}
+	for _, dns := range cfg.DNS {
+		if net.ParseIP(dns) == nil {
+			return fmt.Errorf("invalid DNS server: %q", dns)
+		}
+	}
+	if cfg.MTU != 0 && (cfg.MTU < 576 || cfg.MTU > 1500) {
+		return fmt.Errorf("MTU %d is out of 576-1500 range", cfg.MTU)
+	}
+	if cfg.Mac != "" {
+		if _, err := net.ParseMAC(cfg.Mac); err != nil {
+			return fmt.Errorf("invalid MAC address: %q", cfg.Mac)
+		}
+	}
+
+	return nil
+}
+
+// wanConfigPayload is a raw WAN configuration API response.
+type wanConfigPayload struct {
+	Info struct {
+		Mac     string `json:"mac"`
+		Details struct {
+			WANType  string          `json:"wanType"`
+			Username string          `json:"username"`
+			IP       string          `json:"ipaddr"`
+			Mask     string          `json:"netmask"`
+			Gateway  string          `json:"gateway"`
+			DNS1     string          `json:"dns1"`
+			DNS2     string          `json:"dns2"`
+			PeerDNS  string          `json:"peerdns"`
+			MTU      json.RawMessage `json:"mtu"`
+		} `json:"details"`
+	} `json:"info"`
+}
+
+// WANConfig returns WAN connection configuration, PPPoE password is not
+// exposed by the router.
+func (c *Client) WANConfig() (WANConfig, error) {
+	var payload wanConfigPayload
+
+	if err := c.get("/api/xqnetwork/wan_info", nil, &payload); err != nil {
+		return WANConfig{}, err
+	}
+
+	details := payload.Info.Details
+	cfg := WANConfig{
+		Mode:     details.WANType,
+		Username: details.Username,
+		Mac:      payload.Info.Mac,
+	}
+	if details.WANType == WANModeStatic {
+		cfg.IP = details.IP
+		cfg.Mask = details.Mask
+		cfg.Gateway = details.Gateway
+	}
+	if details.PeerDNS != "1" {
+		for _, dns := range []string{details.DNS1, details.DNS2} {
+			if dns != "" {
+				cfg.DNS = append(cfg.DNS, dns)
+			}
+		}
+	}
+	cfg.MTU = int(parseNumber(details.MTU))
+
+	return cfg, nil
+}
+
+// SetWANConfig validates and applies WAN connection configuration,
+// MAC address is applied separately by CloneMac.
+func (c *Client) SetWANConfig(cfg WANConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("wanType", cfg.Mode)
+
+	switch cfg.Mode {
+	case WANModePPPoE:
+		params.Set("pppoeName", cfg.Username)
+		params.Set("pppoePwd", cfg.Password)
+	case WANModeStatic:
+		params.Set("staticIp", cfg.IP)
+		params.Set("staticMask", cfg.Mask)
+		params.Set("staticGateway", cfg.Gateway)
+	}
+
+	params.Set("autoset", "1")
+	if len(cfg.DNS) > 0 {
+		params.Set("autoset", "0")
+		params.Set("dns1", cfg.DNS[0])
+		if len(cfg.DNS) > 1 {
+			params.Set("dns2", cfg.DNS[1])
+		}
+	}
+	if cfg.MTU > 0 {
+		params.Set("mtu", strconv.Itoa(cfg.MTU))
+	}
+
+	return c.post("/api/xqnetwork/set_wan", params, nil)
+}
+
+// CloneMac sets WAN interface MAC address.
+func (c *Client) CloneMac(mac string) error {
+	if _, err := net.ParseMAC(mac); err != nil {
+		return fmt.Errorf("invalid MAC address: %q", mac)
+	}
+
+	params := url.Values{}
+	params.Set("mac", mac)
+
+	return c.post("/api/xqnetwork/mac_clone", params, nil)
+}
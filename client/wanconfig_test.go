@@ -0,0 +1,158 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWANConfig_Validate(t *testing.T) {
+	for name, cfg := range map[string]WANConfig{
+		"dhcp":   {Mode: WANModeDHCP},
+		"pppoe":  {Mode: WANModePPPoE, Username: "user", Password: "secret", MTU: 1480},
+		"static": {Mode: WANModeStatic, IP: "203.0.113.10", Mask: "255.255.255.0", Gateway: "203.0.113.1", DNS: []string{"1.1.1.1"}},
+		"clone":  {Mode: WANModeDHCP, Mac: "00:11:22:33:44:55", DNS: []string{"8.8.8.8", "8.8.4.4"}},
+	} {
+		assert.NoError(t, cfg.Validate(), name)
+	}
+
+	for name, cfg := range map[string]WANConfig{
+		"unknown mode":     {Mode: "l2tp"},
+		"pppoe no user":    {Mode: WANModePPPoE, Password: "secret"},
+		"static no dns":    {Mode: WANModeStatic, IP: "203.0.113.10", Mask: "255.255.255.0", Gateway: "203.0.113.1"},
+		"gateway outside":  {Mode: WANModeStatic, IP: "203.0.113.10", Mask: "255.255.255.0", Gateway: "198.51.100.1", DNS: []string{"1.1.1.1"}},
+		"invalid dns":      {Mode: WANModeDHCP, DNS: []string{"dns.example.com"}},
+		"too many dns":     {Mode: WANModeDHCP, DNS: []string{"1.1.1.1", "1.0.0.1", "8.8.8.8"}},
+		"mtu out of range": {Mode: WANModeDHCP, MTU: 9000},
+		"invalid mac":      {Mode: WANModeDHCP, Mac: "00:11:22"},
+	} {
+		assert.Error(t, cfg.Validate(), name)
+	}
+}
+
+func TestClient_WANConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Expected path
+			assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/wan_info")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			w.Write([]byte(`
+				{
+					"info": {
+						"mac": "00:11:22:33:44:55",
+						"details": {
+							"wanType": "pppoe",
+							"username": "user",
+							"dns1": "1.1.1.1",
+							"peerdns": "0",
+							"mtu": "1480"
+						}
+					},
+					"code": 0
+				}
+			`))
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		cfg, err := c.WANConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, WANConfig{
+			Mode:     WANModePPPoE,
+			Username: "user",
+			DNS:      []string{"1.1.1.1"},
+			MTU:      1480,
+			Mac:      "00:11:22:33:44:55",
+		}, cfg)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       ts.URL,
+			nonce:      "nonce",
+			token:      "token",
+		}
+
+		_, err := c.WANConfig()
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_SetWANConfig(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected method
+		assert.Equal(t, "POST", r.Method)
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/set_wan")
+		// Expected form params
+		assert.Equal(t, "static", r.FormValue("wanType"))
+		assert.Equal(t, "203.0.113.10", r.FormValue("staticIp"))
+		assert.Equal(t, "255.255.255.0", r.FormValue("staticMask"))
+		assert.Equal(t, "203.0.113.1", r.FormValue("staticGateway"))
+		assert.Equal(t, "0", r.FormValue("autoset"))
+		assert.Equal(t, "1.1.1.1", r.FormValue("dns1"))
+		assert.Equal(t, "1.0.0.1", r.FormValue("dns2"))
+		assert.Equal(t, "1400", r.FormValue("mtu"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.SetWANConfig(WANConfig{
+		Mode:    WANModeStatic,
+		IP:      "203.0.113.10",
+		Mask:    "255.255.255.0",
+		Gateway: "203.0.113.1",
+		DNS:     []string{"1.1.1.1", "1.0.0.1"},
+		MTU:     1400,
+	}))
+}
+
+func TestClient_CloneMac(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Expected path
+		assert.Equal(t, r.URL.Path, "/cgi-bin/luci/;stok=token/api/xqnetwork/mac_clone")
+		// Expected form params
+		assert.Equal(t, "00:11:22:33:44:66", r.FormValue("mac"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       ts.URL,
+		nonce:      "nonce",
+		token:      "token",
+	}
+
+	assert.NoError(t, c.CloneMac("00:11:22:33:44:66"))
+	assert.Error(t, c.CloneMac("invalid"))
+}
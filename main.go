@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"os/user"
 	"strings"
 	"syscall"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 
 	"miwifi-termui/app"
+	"miwifi-termui/client"
 )
 
 const logFile = "miwifi.out.log"
@@ -27,13 +29,26 @@ func main() {
 	var (
 		versionFlag  = flag.Bool("version", false, "application version")
 		debugFlag    = flag.Bool("debug", false, "run application in debug mode")
-		hostFlag     = flag.String("host", "", "MiWiFi host address")
+		hostFlag     = flag.String("host", "", "MiWiFi host address or URL (e.g. https://[fd00::1]:8443/router), router is discovered on the local network when empty")
 		usernameFlag = flag.String("username", "admin", "username for login")
 		passwordFlag = flag.String("password", "", "password for login")
-		intervalFlag = flag.Duration("interval", time.Second*10, "fetch data interval")
-		uiFlag       = flag.String("ui", "dash", `ui controller {"dash", "cpu", "dev", "info", "mem", "net"}`)
+		caFlag       = flag.String("ca", "", "PEM bundle of CA certificates trusted for HTTPS host")
+		certFlag     = flag.String("cert", "", "PEM client certificate for HTTPS host")
+		keyFlag      = flag.String("key", "", "PEM client certificate key for HTTPS host")
+		insecureFlag = flag.Bool("insecure", false, "skip HTTPS host certificate verification")
+		proxyFlag    = flag.String("proxy", "", "HTTP, HTTPS or SOCKS5 proxy URL, e.g. socks5://127.0.0.1:1080")
+		sshFlag      = flag.String("ssh", "", "SSH jump host the router is reached through, [user@]host[:port]")
+		sshKeyFlag   = flag.String("ssh-key", "", "SSH private key, SSH agent is used when empty")
+		sshHostsFlag = flag.String("ssh-known-hosts", "", "SSH known hosts file (default ~/.ssh/known_hosts)")
+		sshInsecure  = flag.Bool("ssh-insecure", false, "skip SSH jump host key verification")
+		profileFlag  = flag.String("profile", "", "connection profile name, flags override profile settings")
+		saveFlag     = flag.Bool("save-profile", false, "save host, username and connection flags to the profile")
+		intervalFlag = flag.Duration("interval", time.Second*10, "fetch data interval, changed by +/- keys in UI")
+		adaptiveFlag = flag.Bool("adaptive", false, "slow down fetching while router is slow or failing, toggled by * key in UI")
+		uiFlag       = flag.String("ui", "dash", `ui controller {"dash", "cpu", "dev", "info", "mem", "net", "portfwd", "dhcp", "logs", "topo", "upnp", "lan", "wan"}`)
 	)
 
+	flag.Usage = usage
 	flag.Parse()
 
 	if *versionFlag {
@@ -41,10 +56,75 @@ func main() {
 		os.Exit(0)
 	}
 
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var profile app.Profile
+	if *profileFlag != "" {
+		cfg, err := app.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't load config: %v\n", err)
+			os.Exit(2)
+		}
+		p, ok := cfg.Profiles[*profileFlag]
+		if !ok && !*saveFlag {
+			fmt.Fprintf(os.Stderr, "profile %q not found\n", *profileFlag)
+			os.Exit(2)
+		}
+		profile = p
+	} else if *saveFlag {
+		fmt.Fprintln(os.Stderr, "-save-profile requires -profile")
+		os.Exit(2)
+	}
+
+	if !set["host"] {
+		*hostFlag = profile.Host
+	}
+	if !set["username"] && profile.Username != "" {
+		*usernameFlag = profile.Username
+	}
+
+	opts := profile.Transport
+	if set["ca"] {
+		opts.CAFile = *caFlag
+	}
+	if set["cert"] {
+		opts.CertFile = *certFlag
+	}
+	if set["key"] {
+		opts.KeyFile = *keyFlag
+	}
+	if set["insecure"] {
+		opts.InsecureSkipVerify = *insecureFlag
+	}
+	if set["proxy"] {
+		opts.Proxy = *proxyFlag
+	}
+	if set["ssh"] {
+		opts.SSH = parseSSHTarget(*sshFlag)
+	}
+	if opts.SSH != nil {
+		if set["ssh-key"] {
+			opts.SSH.KeyFile = *sshKeyFlag
+		}
+		if set["ssh-known-hosts"] {
+			opts.SSH.KnownHostsFile = *sshHostsFlag
+		}
+		if set["ssh-insecure"] {
+			opts.SSH.InsecureIgnoreHostKey = *sshInsecure
+		}
+	}
+
 	var prompt bool
 
+	reader := bufio.NewReader(os.Stdin)
+
+	// discovery is useless for routers behind proxy or tunnel
+	if *hostFlag == "" && opts.Proxy == "" && opts.SSH == nil {
+		*hostFlag = app.DiscoverHost(reader)
+	}
+
 	if *hostFlag == "" {
-		reader := bufio.NewReader(os.Stdin)
 		fmt.Print("Enter host: ")
 		host, err := reader.ReadString('\n')
 		if err != nil {
@@ -54,8 +134,24 @@ func main() {
 		prompt = true
 	}
 
-	if !strings.HasPrefix(*hostFlag, "http://") {
-		*hostFlag = "http://" + *hostFlag
+	if *saveFlag {
+		cfg, err := app.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't load config: %v\n", err)
+			os.Exit(2)
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]app.Profile)
+		}
+		cfg.Profiles[*profileFlag] = app.Profile{
+			Host:      *hostFlag,
+			Username:  *usernameFlag,
+			Transport: opts,
+		}
+		if err := app.SaveConfig(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "can't save profile: %v\n", err)
+			os.Exit(2)
+		}
 	}
 
 	if *passwordFlag == "" {
@@ -85,10 +181,66 @@ func main() {
 		logger.Out = file
 	}
 
-	a := app.New(getMacAddr(), *hostFlag, *usernameFlag, *passwordFlag, *intervalFlag, logger)
+	a, err := app.New(getMacAddr(), *hostFlag, opts, *usernameFlag, *passwordFlag, *intervalFlag, *adaptiveFlag, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if flag.NArg() > 0 {
+		os.Exit(a.Exec(flag.Args()))
+	}
 	os.Exit(a.Run(*uiFlag))
 }
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Usage: %s [flags] [command]
+
+Commands:
+  portfwd list                     print port forwarding rules as JSON
+  portfwd add -name -port -ip      add port forwarding rule
+  portfwd rm -port [-proto]        remove port forwarding rule
+  firmware check                   check firmware update, exits with code 10
+                                   when update is available
+  firmware status                  print firmware upgrade progress
+  logs [-follow]                   print router system log
+  backup [-o file]                 save router configuration backup
+  restore [-force] file            restore router configuration from backup
+  system show                      print router name, clock and time settings
+  system sync                      set router clock to local time
+  system set [-name] [-tz] [-ntp]  change router name, timezone or NTP servers
+  passwd                           change router admin password
+  capabilities [-refresh]          print API groups supported by the router
+  api [-q selector] METHOD PATH    call any API resource and print JSON response,
+      [key=value ...]              e.g. api -q .info GET /api/xqnetwork/wifi_detail_all
+  api                              start interactive API explorer
+
+Without command the terminal UI is started.
+
+Connection settings can be saved to a profile with -profile name -save-profile
+and used later with -profile name, the password and proxy credentials are
+never saved, pass -proxy with credentials on each run instead.
+
+Flags:
+`, os.Args[0])
+	flag.PrintDefaults()
+}
+
+// parseSSHTarget parses [user@]host[:port] jump host, user defaults to the
+// current user.
+func parseSSHTarget(target string) *client.SSHOptions {
+	if target == "" {
+		return nil
+	}
+
+	opts := client.SSHOptions{Address: target}
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		opts.User, opts.Address = target[:i], target[i+1:]
+	} else if u, err := user.Current(); err == nil {
+		opts.User = u.Username
+	}
+	return &opts
+}
+
 func getMacAddr() (addr string) {
 	interfaces, err := net.Interfaces()
 	if err == nil {
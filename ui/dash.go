@@ -9,22 +9,41 @@ import (
 	"miwifi-termui/client"
 )
 
-func NewDashboard(streamStat StreamStatRead, streamBand StreamBandRead) *dashboardController {
+func NewDashboard(
+	streamStat StreamStatRead,
+	streamBand StreamBandRead,
+	streamDevices StreamDevicesRead,
+	streamQoS StreamQoSRead,
+	streamWAN StreamWANRead,
+	streamROM StreamROMRead,
+	streamTime StreamTimeRead,
+) *dashboardController {
 	ctl := &dashboardController{
-		Grid:       ui.NewGrid(),
-		streamStat: streamStat,
-		streamBand: streamBand,
+		Grid:          ui.NewGrid(),
+		streamStat:    streamStat,
+		streamBand:    streamBand,
+		streamDevices: streamDevices,
+		streamQoS:     streamQoS,
+		streamWAN:     streamWAN,
+		streamROM:     streamROM,
+		streamTime:    streamTime,
 	}
 
 	devStreamsStat := make(chan client.Stat, 1)
-	ctl.dev = NewDevController(devStreamsStat)
+	devStreamsDevices := make(chan []client.DeviceInfo, 1)
+	devStreamsQoS := make(chan client.QoS, 1)
+	ctl.dev = NewDevController(devStreamsStat, devStreamsDevices, devStreamsQoS, nil, nil)
 	ctl.streamsStat = append(ctl.streamsStat, devStreamsStat)
+	ctl.streamsDevices = append(ctl.streamsDevices, devStreamsDevices)
+	ctl.streamsQoS = append(ctl.streamsQoS, devStreamsQoS)
 
 	netStreamsStat := make(chan client.Stat, 1)
 	netStreamsBand := make(chan client.Band, 1)
-	ctl.net = NewNETController(netStreamsStat, netStreamsBand)
+	netStreamsWAN := make(chan client.WANInfo, 1)
+	ctl.net = NewNETController(netStreamsStat, netStreamsBand, netStreamsWAN, NETOptions{})
 	ctl.streamsStat = append(ctl.streamsStat, netStreamsStat)
 	ctl.streamsBand = append(ctl.streamsBand, netStreamsBand)
+	ctl.streamsWAN = append(ctl.streamsWAN, netStreamsWAN)
 
 	cpuStreamsStat := make(chan client.Stat, 1)
 	ctl.cpu = NewCPUController(cpuStreamsStat)
@@ -35,8 +54,18 @@ func NewDashboard(streamStat StreamStatRead, streamBand StreamBandRead) *dashboa
 	ctl.streamsStat = append(ctl.streamsStat, memStreamsStat)
 
 	infoStreamsStat := make(chan client.Stat, 1)
-	ctl.info = NewInfoController(infoStreamsStat)
+	infoStreamsWAN := make(chan client.WANInfo, 1)
+	infoStreamsROM := make(chan client.ROMUpdate, 1)
+	var infoOpts InfoOptions
+	if streamTime != nil {
+		infoStreamsTime := make(chan client.SystemTime, 1)
+		infoOpts.StreamTime = infoStreamsTime
+		ctl.streamsTime = append(ctl.streamsTime, infoStreamsTime)
+	}
+	ctl.info = NewInfoController(infoStreamsStat, infoStreamsWAN, infoStreamsROM, infoOpts)
 	ctl.streamsStat = append(ctl.streamsStat, infoStreamsStat)
+	ctl.streamsWAN = append(ctl.streamsWAN, infoStreamsWAN)
+	ctl.streamsROM = append(ctl.streamsROM, infoStreamsROM)
 
 	return ctl
 }
@@ -50,11 +79,21 @@ type dashboardController struct {
 	mem  Controller
 	info Controller
 
-	streamStat StreamStatRead
-	streamBand StreamBandRead
-
-	streamsStat []StreamStatWrite
-	streamsBand []StreamBandWrite
+	streamStat    StreamStatRead
+	streamBand    StreamBandRead
+	streamDevices StreamDevicesRead
+	streamQoS     StreamQoSRead
+	streamWAN     StreamWANRead
+	streamROM     StreamROMRead
+	streamTime    StreamTimeRead
+
+	streamsStat    []StreamStatWrite
+	streamsBand    []StreamBandWrite
+	streamsDevices []StreamDevicesWrite
+	streamsQoS     []StreamQoSWrite
+	streamsWAN     []StreamWANWrite
+	streamsROM     []StreamROMWrite
+	streamsTime    []StreamTimeWrite
 
 	once sync.Once
 }
@@ -77,12 +116,12 @@ func (c *dashboardController) Init(ctx context.Context) {
 
 func (c *dashboardController) initUI() {
 	c.Grid.Set(
-		ui.NewRow(.1, c.info),
-		ui.NewRow(.5,
+		ui.NewRow(.2, c.info),
+		ui.NewRow(.45,
 			ui.NewCol(.5, c.net),
 			ui.NewCol(.5, c.dev),
 		),
-		ui.NewRow(.4,
+		ui.NewRow(.35,
 			ui.NewCol(.5, c.cpu),
 			ui.NewCol(.5, c.mem),
 		),
@@ -103,6 +142,26 @@ func (c *dashboardController) subscribe(ctx context.Context) {
 				for _, stream := range c.streamsBand {
 					stream <- b
 				}
+			case d := <-c.streamDevices:
+				for _, stream := range c.streamsDevices {
+					stream <- d
+				}
+			case q := <-c.streamQoS:
+				for _, stream := range c.streamsQoS {
+					stream <- q
+				}
+			case w := <-c.streamWAN:
+				for _, stream := range c.streamsWAN {
+					stream <- w
+				}
+			case r := <-c.streamROM:
+				for _, stream := range c.streamsROM {
+					stream <- r
+				}
+			case t := <-c.streamTime:
+				for _, stream := range c.streamsTime {
+					stream <- t
+				}
 			}
 		}
 	})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 
 	ui "github.com/gizak/termui/v3"
@@ -15,14 +16,37 @@ import (
 
 const maxDevices = 16
 
-// NewDevController creates and returns devices status UI controller.
-func NewDevController(streamStat StreamStatRead) *devController {
+// QoSLimiter changes connected devices speed limits.
+type QoSLimiter interface {
+	// SetQoSLimit sets device upload and download speed limits in KB/s.
+	SetQoSLimit(mac string, upload, download uint64) error
+	// ClearQoSLimit removes device speed limits.
+	ClearQoSLimit(mac string) error
+}
+
+// NewDevController creates and returns devices status UI controller,
+// speed limit actions are disabled when limiter is nil and access
+// schedule editor is disabled when parental is nil.
+func NewDevController(
+	streamStat StreamStatRead,
+	streamDevices StreamDevicesRead,
+	streamQoS StreamQoSRead,
+	limiter QoSLimiter,
+	parental ParentalControl,
+) *devController {
 	return &devController{
-		Grid:       ui.NewGrid(),
-		bodyChart:  widgets.NewPieChart(),
-		bodyTable:  widgets.NewTable(),
-		footText:   widgets.NewParagraph(),
-		streamStat: streamStat,
+		Grid:          ui.NewGrid(),
+		bodyChart:     widgets.NewPieChart(),
+		bodyTable:     newSelectTable(),
+		footText:      widgets.NewParagraph(),
+		limitForm:     newForm("Speed limit", "Upload (KB/s)", "Download (KB/s)"),
+		schedule:      newScheduleEditor(parental),
+		streamStat:    streamStat,
+		streamDevices: streamDevices,
+		streamQoS:     streamQoS,
+		limiter:       limiter,
+		parental:      parental,
+		results:       make(chan string, 1),
 	}
 }
 
@@ -30,10 +54,28 @@ type devController struct {
 	*ui.Grid
 
 	bodyChart *widgets.PieChart
-	bodyTable *widgets.Table
+	bodyTable *selectTable
 	footText  *widgets.Paragraph
+	limitForm *form
+	schedule  *scheduleEditor
+
+	streamStat    StreamStatRead
+	streamDevices StreamDevicesRead
+	streamQoS     StreamQoSRead
+	limiter       QoSLimiter
+	parental      ParentalControl
+
+	// results are status messages of finished actions, they are applied
+	// by subscribe like other updates
+	results chan string
 
-	streamStat StreamStatRead
+	stat    client.Stat
+	devices map[string]client.DeviceInfo
+	qos     client.QoS
+	actions string
+	status  string
+	// pending is a device which speed limit removal awaits confirmation
+	pending *client.DeviceStat
 
 	once sync.Once
 }
@@ -41,6 +83,64 @@ type devController struct {
 func (c *devController) Resize() {
 	w, h := ui.TerminalDimensions()
 	c.Grid.SetRect(0, 0, w, h)
+	c.limitForm.Resize(c.GetRect())
+	c.schedule.Resize(c.GetRect())
+}
+
+func (c *devController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	c.limitForm.Draw(buf)
+	c.schedule.Draw(buf)
+}
+
+func (c *devController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.schedule.Handle(e) || c.limitForm.Handle(e) {
+		return true
+	}
+
+	if c.pending != nil {
+		device := *c.pending
+		c.pending = nil
+		c.status = c.actions
+		if e.ID == "y" {
+			c.status = fmt.Sprintf("Removing speed limit of %s...", device.Name)
+			go c.clearLimit(device)
+		}
+		c.update()
+		return true
+	}
+
+	if c.bodyTable.Handle(e) {
+		return true
+	}
+
+	device, ok := c.selectedDevice()
+	if !ok {
+		return false
+	}
+
+	switch {
+	case e.ID == "p" && c.parental != nil:
+		c.schedule.Open(device)
+	case e.ID == "l" && c.limiter != nil:
+		limit, _ := c.qos.Limit(device.Mac)
+		c.limitForm.Open(
+			func(values []string) { c.setLimit(device, values) },
+			strconv.FormatUint(limit.MaxUpload, 10),
+			strconv.FormatUint(limit.MaxDownload, 10),
+		)
+	case e.ID == "c" && c.limiter != nil:
+		c.pending = &device
+		c.status = fmt.Sprintf("Remove speed limit of %s? [y/N]", device.Name)
+		c.update()
+	default:
+		return false
+	}
+
+	return true
 }
 
 func (c *devController) Init(ctx context.Context) {
@@ -54,10 +154,20 @@ func (c *devController) initUI() {
 	c.bodyChart.Data = make([]float64, maxDevices)
 
 	c.bodyTable.Rows = make([][]string, maxDevices+1)
-	c.bodyTable.Rows[0] = []string{"Name", "Value", "Percent"}
+	c.bodyTable.Rows[0] = []string{"Name", "Value", "Percent", "Speed", "Limit", "IP", "Link", "Signal", "Rate"}
 
 	c.footText.Border = false
 
+	var actions []string
+	if c.limiter != nil {
+		actions = append(actions, "[l] limit speed of selected device  [c] clear limit")
+	}
+	if c.parental != nil {
+		actions = append(actions, "[p] internet access schedule")
+	}
+	c.actions = strings.Join(actions, "  ")
+	c.status = c.actions
+
 	c.Grid.Set(
 		ui.NewRow(.8,
 			ui.NewCol(.4, c.bodyChart),
@@ -67,9 +177,11 @@ func (c *devController) initUI() {
 	)
 }
 
-func (c *devController) update(s client.Stat) {
+func (c *devController) update() {
 	var totalDownload float64
 
+	s := c.stat
+
 	c.bodyChart.Data = c.bodyChart.Data[:len(s.Devices)]
 
 	for i, device := range s.Devices {
@@ -89,17 +201,103 @@ func (c *devController) update(s client.Stat) {
 			fmt.Sprintf("[%d] %s", i+1, device.Name),
 			humanize.Bytes(device.Download),
 			fmt.Sprintf("%.2f%%", float64(device.Download)*100/totalDownload),
+			fmt.Sprintf("↓%s/s ↑%s/s", humanize.Bytes(device.DownSpeed), humanize.Bytes(device.UpSpeed)),
+			c.formatLimit(device.Mac),
 		}
+		c.bodyTable.Rows[i+1] = append(c.bodyTable.Rows[i+1], c.formatInfo(device.Mac)...)
 	}
 
 	c.footText.Text = fmt.Sprintf(
-		"Total downloaded: %s | Total uploaded: %s | Devices: %d",
+		"Total downloaded: %s | Total uploaded: %s | Devices: %d\n%s",
 		humanize.Bytes(s.WAN.Download),
 		humanize.Bytes(s.WAN.Upload),
 		len(s.Devices),
+		c.status,
 	)
 }
 
+func (c *devController) formatLimit(mac string) string {
+	limit, ok := c.qos.Limit(mac)
+	if !ok || limit.MaxUpload == 0 && limit.MaxDownload == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("↓%s/s ↑%s/s", formatKBytes(limit.MaxDownload), formatKBytes(limit.MaxUpload))
+}
+
+// formatInfo returns IP, link type, signal strength and rate columns
+// of the device from the full device list.
+func (c *devController) formatInfo(mac string) []string {
+	info, ok := c.devices[strings.ToUpper(mac)]
+	if !ok {
+		return []string{"-", "-", "-", "-"}
+	}
+
+	signal, rate := "-", "-"
+	if info.Type.Wireless() && info.Signal != 0 {
+		color := "green"
+		switch {
+		case info.Signal < -75:
+			color = "red"
+		case info.Signal < -60:
+			color = "yellow"
+		}
+		signal = fmt.Sprintf("[%d dBm](fg:%s)", info.Signal, color)
+	}
+	if info.Rate > 0 {
+		rate = fmt.Sprintf("%d Mbit/s", info.Rate)
+	}
+
+	return []string{info.IPs(), info.Type.String(), signal, rate}
+}
+
+func (c *devController) selectedDevice() (client.DeviceStat, bool) {
+	i := c.bodyTable.SelectedRow
+	if i < 0 || i >= len(c.stat.Devices) || i >= maxDevices {
+		return client.DeviceStat{}, false
+	}
+	return c.stat.Devices[i], true
+}
+
+func (c *devController) setLimit(device client.DeviceStat, values []string) {
+	upload, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		c.status = fmt.Sprintf("Invalid upload limit: %s", values[0])
+		c.update()
+		return
+	}
+	download, err := strconv.ParseUint(values[1], 10, 64)
+	if err != nil {
+		c.status = fmt.Sprintf("Invalid download limit: %s", values[1])
+		c.update()
+		return
+	}
+
+	go func() {
+		if err := c.limiter.SetQoSLimit(device.Mac, upload, download); err != nil {
+			c.results <- fmt.Sprintf("Failed to limit %s: %v", device.Name, err)
+			return
+		}
+		c.results <- fmt.Sprintf("Speed limit of %s is updated", device.Name)
+	}()
+}
+
+func (c *devController) clearLimit(device client.DeviceStat) {
+	if err := c.limiter.ClearQoSLimit(device.Mac); err != nil {
+		c.results <- fmt.Sprintf("Failed to clear %s limit: %v", device.Name, err)
+		return
+	}
+	c.results <- fmt.Sprintf("Speed limit of %s is removed", device.Name)
+}
+
+// formatKBytes returns human readable representation of KB value,
+// zero value is shown as unlimited.
+func formatKBytes(v uint64) string {
+	if v == 0 {
+		return "∞"
+	}
+	return humanize.Bytes(v * 1024)
+}
+
 func (c *devController) subscribe(ctx context.Context) {
 	c.once.Do(func() {
 		for {
@@ -107,7 +305,29 @@ func (c *devController) subscribe(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case s := <-c.streamStat:
-				c.update(s)
+				c.Lock()
+				c.stat = s
+				c.update()
+				c.Unlock()
+			case d := <-c.streamDevices:
+				devices := make(map[string]client.DeviceInfo, len(d))
+				for _, info := range d {
+					devices[strings.ToUpper(info.Mac)] = info
+				}
+				c.Lock()
+				c.devices = devices
+				c.update()
+				c.Unlock()
+			case q := <-c.streamQoS:
+				c.Lock()
+				c.qos = q
+				c.update()
+				c.Unlock()
+			case status := <-c.results:
+				c.Lock()
+				c.status = status
+				c.update()
+				c.Unlock()
 			}
 		}
 	})
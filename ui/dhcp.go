@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+// LeaseManager manages DHCP leases and static reservations.
+type LeaseManager interface {
+	DHCPLeases() ([]client.DHCPLease, error)
+	StaticLeases() ([]client.StaticLease, error)
+	AddStaticLease(lease client.StaticLease) error
+	RemoveStaticLease(mac string) error
+}
+
+// NewDHCPController creates and returns DHCP leases UI controller.
+func NewDHCPController(streamStat StreamStatRead, leases LeaseManager) *dhcpController {
+	return &dhcpController{
+		Grid:       ui.NewGrid(),
+		bodyTable:  newSelectTable(),
+		footText:   widgets.NewParagraph(),
+		streamStat: streamStat,
+		leases:     leases,
+		results:    make(chan string, 1),
+	}
+}
+
+type dhcpController struct {
+	*ui.Grid
+
+	bodyTable *selectTable
+	footText  *widgets.Paragraph
+
+	streamStat StreamStatRead
+	leases     LeaseManager
+
+	// results are status messages of finished actions, subscribe applies
+	// them and reloads leases, so leases are loaded by single goroutine
+	results chan string
+
+	stat   client.Stat
+	rows   []leaseRow
+	status string
+	// pending is a reservation which removal awaits confirmation
+	pending *leaseRow
+
+	once sync.Once
+}
+
+// leaseRow is a device joined with its DHCP lease or static reservation.
+type leaseRow struct {
+	name     string
+	mac      string
+	ip       string
+	hostname string
+	expires  time.Time
+	static   bool
+	online   bool
+}
+
+func (c *dhcpController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+}
+
+func (c *dhcpController) Init(ctx context.Context) {
+	c.initUI()
+	go c.subscribe(ctx)
+}
+
+func (c *dhcpController) initUI() {
+	c.bodyTable.Title = "DHCP leases"
+	c.bodyTable.Rows = [][]string{{"Name", "MAC address", "IP address", "Hostname", "Lease expiry"}}
+
+	c.footText.Border = false
+	c.updateFooter()
+
+	c.Grid.Set(
+		ui.NewRow(.8, c.bodyTable),
+		ui.NewRow(.2, c.footText),
+	)
+}
+
+func (c *dhcpController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.pending != nil {
+		row := *c.pending
+		c.pending = nil
+		c.status = ""
+		if e.ID == "y" {
+			go c.apply(func() error { return c.leases.RemoveStaticLease(row.mac) }, row.name+" reservation is removed")
+		}
+		c.updateFooter()
+		return true
+	}
+
+	if c.bodyTable.Handle(e) {
+		return true
+	}
+
+	row, ok := c.selectedRow()
+	if !ok {
+		return false
+	}
+
+	switch e.ID {
+	case "s":
+		if row.static || row.ip == "" {
+			return false
+		}
+		lease := client.StaticLease{Mac: row.mac, IP: row.ip, Name: row.name}
+		go c.apply(func() error { return c.leases.AddStaticLease(lease) }, row.ip+" is reserved for "+row.name)
+	case "d":
+		if !row.static {
+			return false
+		}
+		c.pending = &row
+		c.status = fmt.Sprintf("Remove %s reservation of %s? [y/N]", row.ip, row.name)
+		c.updateFooter()
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *dhcpController) selectedRow() (leaseRow, bool) {
+	i := c.bodyTable.SelectedRow
+	if i < 0 || i >= len(c.rows) {
+		return leaseRow{}, false
+	}
+	return c.rows[i], true
+}
+
+func (c *dhcpController) apply(action func() error, done string) {
+	if err := action(); err != nil {
+		c.results <- fmt.Sprintf("Failed: %v", err)
+		return
+	}
+	c.results <- done
+}
+
+// refresh loads leases, it's called by subscribe only.
+func (c *dhcpController) refresh() {
+	leases, err := c.leases.DHCPLeases()
+	if err != nil {
+		c.fail(fmt.Sprintf("Failed to load leases: %v", err))
+		return
+	}
+	static, err := c.leases.StaticLeases()
+	if err != nil {
+		c.fail(fmt.Sprintf("Failed to load reservations: %v", err))
+		return
+	}
+
+	rows := joinLeases(c.stat.Devices, leases, static)
+
+	c.Lock()
+	c.update(rows)
+	c.Unlock()
+}
+
+func (c *dhcpController) fail(status string) {
+	c.Lock()
+	c.status = status
+	c.updateFooter()
+	c.Unlock()
+}
+
+func (c *dhcpController) update(rows []leaseRow) {
+	c.rows = rows
+
+	c.bodyTable.Rows = c.bodyTable.Rows[:1]
+	for _, r := range rows {
+		name := r.name
+		if r.online {
+			name = fmt.Sprintf("[%s](fg:green)", name)
+		}
+
+		expiry := "-"
+		switch {
+		case r.static:
+			expiry = "static"
+		case !r.expires.IsZero():
+			expiry = time.Until(r.expires).Round(time.Minute).String()
+		}
+
+		c.bodyTable.Rows = append(c.bodyTable.Rows, []string{name, r.mac, r.ip, r.hostname, expiry})
+	}
+
+	c.updateFooter()
+}
+
+func (c *dhcpController) updateFooter() {
+	c.footText.Text = fmt.Sprintf(
+		"Leases: %d | [s] make selected lease static  [d] remove reservation\n%s",
+		len(c.rows),
+		c.status,
+	)
+}
+
+func (c *dhcpController) subscribe(ctx context.Context) {
+	c.once.Do(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-c.streamStat:
+				c.stat = s
+				c.refresh()
+			case status := <-c.results:
+				c.Lock()
+				c.status = status
+				c.Unlock()
+				c.refresh()
+			}
+		}
+	})
+}
+
+// joinLeases joins connected devices with DHCP leases and static
+// reservations by MAC address, connected devices go first.
+func joinLeases(devices []client.DeviceStat, leases []client.DHCPLease, static []client.StaticLease) []leaseRow {
+	var rows []leaseRow
+	index := make(map[string]int)
+
+	row := func(mac string) *leaseRow {
+		key := strings.ToUpper(mac)
+		if i, ok := index[key]; ok {
+			return &rows[i]
+		}
+		index[key] = len(rows)
+		rows = append(rows, leaseRow{mac: key})
+		return &rows[len(rows)-1]
+	}
+
+	for _, d := range devices {
+		r := row(d.Mac)
+		r.name = d.Name
+		r.online = d.IsOnline()
+	}
+	for _, l := range leases {
+		r := row(l.Mac)
+		r.ip = l.IP
+		r.hostname = l.Hostname
+		r.expires = l.ExpiresAt()
+	}
+	for _, l := range static {
+		r := row(l.Mac)
+		r.ip = l.IP
+		r.static = true
+		if r.name == "" {
+			r.name = l.Name
+		}
+	}
+
+	for i := range rows {
+		if rows[i].name == "" {
+			rows[i].name = rows[i].hostname
+		}
+	}
+
+	return rows
+}
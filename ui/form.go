@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// newForm creates and returns modal input form with the given field labels.
+func newForm(title string, labels ...string) *form {
+	f := &form{
+		Paragraph: widgets.NewParagraph(),
+		labels:    labels,
+		values:    make([]string, len(labels)),
+		secret:    make([]bool, len(labels)),
+	}
+	f.Title = title
+	f.PaddingLeft = 1
+	f.BorderStyle.Fg = ui.ColorYellow
+	return f
+}
+
+// form is a modal input form drawn over a UI controller.
+type form struct {
+	*widgets.Paragraph
+
+	labels []string
+	values []string
+	secret []bool // fields which values are masked
+	focus  int
+	active bool
+	submit func(values []string)
+}
+
+// Secret masks values of the fields with the given indexes, e.g. passwords.
+func (f *form) Secret(fields ...int) *form {
+	for _, i := range fields {
+		f.secret[i] = true
+	}
+	return f
+}
+
+// Open shows form with initial values, submit is called with entered values.
+func (f *form) Open(submit func(values []string), values ...string) {
+	for i := range f.values {
+		f.values[i] = ""
+		if i < len(values) {
+			f.values[i] = values[i]
+		}
+	}
+	f.focus = 0
+	f.active = true
+	f.submit = submit
+}
+
+// Active reports whether form is shown.
+func (f *form) Active() bool {
+	return f.active
+}
+
+// Resize places form in the center of the given area.
+func (f *form) Resize(area image.Rectangle) {
+	w, h := area.Dx()/2, len(f.labels)+4
+	x, y := area.Min.X+(area.Dx()-w)/2, area.Min.Y+(area.Dy()-h)/2
+	f.SetRect(x, y, x+w, y+h)
+}
+
+// Handle processes keyboard input when form is active.
+func (f *form) Handle(e ui.Event) bool {
+	if !f.active || e.Type != ui.KeyboardEvent {
+		return false
+	}
+
+	switch e.ID {
+	case "<Escape>":
+		f.active = false
+	case "<Enter>":
+		f.active = false
+		f.submit(append([]string(nil), f.values...))
+	case "<Tab>", "<Down>":
+		f.focus = (f.focus + 1) % len(f.labels)
+	case "<Up>":
+		f.focus = (f.focus + len(f.labels) - 1) % len(f.labels)
+	case "<Backspace>", "<C-<Backspace>>":
+		if value := []rune(f.values[f.focus]); len(value) > 0 {
+			f.values[f.focus] = string(value[:len(value)-1])
+		}
+	case "<Space>":
+		f.values[f.focus] += " "
+	default:
+		if len([]rune(e.ID)) == 1 {
+			f.values[f.focus] += e.ID
+		}
+	}
+
+	return true
+}
+
+func (f *form) Draw(buf *ui.Buffer) {
+	if !f.active {
+		return
+	}
+
+	var text strings.Builder
+	for i, label := range f.labels {
+		value := f.values[i]
+		if f.secret[i] {
+			value = strings.Repeat("*", len([]rune(value)))
+		}
+		if i == f.focus {
+			fmt.Fprintf(&text, "[%s: %s_](fg:yellow)\n", label, value)
+		} else {
+			fmt.Fprintf(&text, "%s: %s\n", label, value)
+		}
+	}
+	text.WriteString("\n[Enter] save  [Tab] next  [Esc] cancel")
+	f.Text = text.String()
+
+	buf.Fill(ui.NewCell(' '), f.GetRect())
+	f.Paragraph.Draw(buf)
+}
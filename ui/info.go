@@ -2,7 +2,11 @@ package ui
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strings"
 	"sync"
+	"time"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
@@ -10,12 +14,55 @@ import (
 	"miwifi-termui/client"
 )
 
+// clockSkewTolerance is a router clock offset which is treated as in sync.
+const clockSkewTolerance = 2 * time.Second
+
+// SystemClock reads router name and sets router clock.
+type SystemClock interface {
+	RouterName() (string, error)
+	SetSystemTime(t time.Time) error
+}
+
+// IPv6Configurator changes router IPv6 configuration.
+type IPv6Configurator interface {
+	SetIPv6Config(cfg client.IPv6Config) error
+}
+
+// InfoOptions are optional info UI controller sections, a section is
+// hidden when its stream is nil.
+type InfoOptions struct {
+	// StreamTime shows router clock.
+	StreamTime StreamTimeRead
+	// Clock shows router name and enables clock sync.
+	Clock SystemClock
+	// StreamIPv6 shows IPv6 state.
+	StreamIPv6 StreamIPv6Read
+	// Configurator enables IPv6 mode switching.
+	Configurator IPv6Configurator
+}
+
 // NewInfoController creates and returns info UI controller.
-func NewInfoController(streamStat StreamStatRead) *infoController {
+func NewInfoController(
+	streamStat StreamStatRead,
+	streamWAN StreamWANRead,
+	streamROM StreamROMRead,
+	opts InfoOptions,
+) *infoController {
 	return &infoController{
-		Grid:       ui.NewGrid(),
-		bodyTable:  widgets.NewTable(),
-		streamStat: streamStat,
+		Grid:      ui.NewGrid(),
+		bodyTable: widgets.NewTable(),
+		changelog: widgets.NewParagraph(),
+		ipv6Form: newForm("IPv6 configuration",
+			"Mode (off/native/static/nat)", "Address (static, CIDR)", "Gateway (static)",
+			"LAN prefix (static)", "DNS (static, comma separated)"),
+		streamStat:   streamStat,
+		streamWAN:    streamWAN,
+		streamROM:    streamROM,
+		streamTime:   opts.StreamTime,
+		clock:        opts.Clock,
+		name:         "-",
+		streamIPv6:   opts.StreamIPv6,
+		configurator: opts.Configurator,
 	}
 }
 
@@ -23,36 +70,314 @@ type infoController struct {
 	*ui.Grid
 
 	bodyTable *widgets.Table
+	changelog *widgets.Paragraph
+	ipv6Form  *form
 
-	streamStat StreamStatRead
-	once       sync.Once
+	streamStat   StreamStatRead
+	streamWAN    StreamWANRead
+	streamROM    StreamROMRead
+	streamTime   StreamTimeRead
+	clock        SystemClock
+	streamIPv6   StreamIPv6Read
+	configurator IPv6Configurator
+	wan          wanTracker
+	stat         client.Stat
+	rom          client.ROMUpdate
+	name         string
+	time         client.SystemTime
+	ipv6         client.IPv6Info
+	status       string
+	ipv6Status   string
+	wanRow       int
+	timeRow      int
+	ipv6Row      int
+	showLog      bool
+	once         sync.Once
 }
 
 func (c *infoController) Resize() {
 	w, h := ui.TerminalDimensions()
 	c.Grid.SetRect(0, 0, w, h)
+	c.ipv6Form.Resize(c.GetRect())
+
+	area := c.GetRect()
+	c.changelog.SetRect(area.Min.X+2, area.Min.Y+area.Dy()/3, area.Max.X-2, area.Max.Y-1)
+}
+
+func (c *infoController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	if c.showLog {
+		buf.Fill(ui.NewCell(' '), c.changelog.GetRect())
+		c.changelog.Draw(buf)
+	}
+	c.ipv6Form.Draw(buf)
 }
 
 func (c *infoController) Init(ctx context.Context) {
 	c.initUI()
 	go c.subscribe(ctx)
+	if c.clock != nil {
+		go c.refreshName()
+	}
 }
 
 func (c *infoController) initUI() {
 	c.bodyTable.Title = "Info"
 	c.bodyTable.Rows = make([][]string, 2)
 	c.bodyTable.Rows[0] = []string{"Platform", "System version", "MAC address", "SN"}
+	c.bodyTable.Rows[1] = make([]string, 4)
+	c.bodyTable.RowSeparator = false
+	c.bodyTable.RowStyles[0] = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierBold)
+	if c.streamWAN != nil {
+		c.wanRow = c.appendSection("WAN", "Public IP", "Gateway", "DNS")
+	}
+
+	c.changelog.BorderStyle.Fg = ui.ColorYellow
+	c.changelog.PaddingLeft = 1
+
+	var actions []string
+	if c.clock != nil {
+		actions = append(actions, "[s] sync router clock to local time")
+	}
+	if c.streamTime != nil {
+		c.timeRow = c.appendSection("Router name", "Router time", "Clock skew", "Timezone / NTP")
+	}
+	if c.streamIPv6 != nil {
+		if c.configurator != nil {
+			actions = append(actions, "[6] switch IPv6 mode")
+		}
+		c.ipv6Row = c.appendSection("IPv6", "Delegated prefix", "WAN address", "LAN address")
+	}
+	if len(actions) > 0 {
+		c.bodyTable.Title += " | " + strings.Join(actions, "  ")
+	}
 
 	c.Grid.Set(ui.NewRow(1.0, c.bodyTable))
 }
 
-func (c *infoController) update(s client.Stat) {
+func (c *infoController) update() {
+	version := c.stat.Hardware.Version
+	if c.rom.Available() && c.rom.Version != version {
+		version = fmt.Sprintf("%s [(%s available, c: changelog)](fg:black,bg:yellow)", version, c.rom.Version)
+	}
+
+	c.changelog.Title = fmt.Sprintf("Firmware %s changelog | [c] close", c.rom.Version)
+	c.changelog.Text = strings.TrimSpace(c.rom.ChangeLog)
+	if c.changelog.Text == "" {
+		c.changelog.Text = "Changelog is not provided"
+	}
+	c.showLog = c.showLog && c.rom.Available()
+
 	c.bodyTable.Rows[1] = []string{
-		s.Hardware.Platform,
-		s.Hardware.Version,
-		s.Hardware.Mac,
-		s.Hardware.SN,
+		c.stat.Hardware.Platform,
+		version,
+		c.stat.Hardware.Mac,
+		c.stat.Hardware.SN,
+	}
+}
+
+func (c *infoController) updateWAN(w client.WANInfo) {
+	c.wan.update(w)
+	if c.wanRow == 0 {
+		return
+	}
+
+	c.bodyTable.Rows[c.wanRow] = []string{
+		fmt.Sprintf("%s %s, uptime %s",
+			c.wan.field("type", strings.ToUpper(w.Type)),
+			c.wan.field("state", wanState(w)),
+			w.UpTime.Truncate(time.Second),
+		),
+		c.wan.field("ip", w.IP),
+		c.wan.field("gateway", w.Gateway),
+		c.wan.field("dns", strings.Join(w.DNS, ", ")),
+	}
+}
+
+// appendSection adds header row with empty values row to the table
+// and returns values row index.
+func (c *infoController) appendSection(header ...string) int {
+	c.bodyTable.Rows = append(c.bodyTable.Rows, header, make([]string, len(header)))
+	c.bodyTable.RowStyles[len(c.bodyTable.Rows)-2] = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierBold)
+	return len(c.bodyTable.Rows) - 1
+}
+
+func (c *infoController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.ipv6Form.Handle(e) {
+		return true
+	}
+
+	switch {
+	case e.ID == "c" && c.rom.Available():
+		c.showLog = !c.showLog
+	case e.ID == "<Escape>" && c.showLog:
+		c.showLog = false
+	case e.ID == "s" && c.clock != nil:
+		go c.syncTime()
+	case e.ID == "6" && c.configurator != nil && c.streamIPv6 != nil:
+		var address string
+		if len(c.ipv6.WANAddress) > 0 {
+			address = ipv6CIDR(c.ipv6.WANAddress[0], c.ipv6.Prefix)
+		}
+		c.ipv6Form.Open(c.saveIPv6, c.ipv6.Mode, address, c.ipv6.Gateway, c.ipv6.Prefix, strings.Join(c.ipv6.DNS, ","))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ipv6CIDR returns address in CIDR notation expected by IPv6 form, prefix
+// length is taken from the delegated prefix when address has none.
+func ipv6CIDR(address, prefix string) string {
+	if address == "" || strings.Contains(address, "/") {
+		return address
+	}
+	ones := 64
+	if _, network, err := net.ParseCIDR(prefix); err == nil {
+		ones, _ = network.Mask.Size()
+	}
+	return fmt.Sprintf("%s/%d", address, ones)
+}
+
+func (c *infoController) saveIPv6(values []string) {
+	cfg := client.IPv6Config{
+		Mode:    strings.ToLower(strings.TrimSpace(values[0])),
+		Address: strings.TrimSpace(values[1]),
+		Gateway: strings.TrimSpace(values[2]),
+		Prefix:  strings.TrimSpace(values[3]),
+	}
+	for _, dns := range strings.Split(values[4], ",") {
+		if dns = strings.TrimSpace(dns); dns != "" {
+			cfg.DNS = append(cfg.DNS, dns)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		c.ipv6Status = err.Error()
+		c.updateIPv6(c.ipv6)
+		return
+	}
+
+	go func() {
+		status := fmt.Sprintf("switched to %s", cfg.Mode)
+		if err := c.configurator.SetIPv6Config(cfg); err != nil {
+			status = fmt.Sprintf("switch failed: %v", err)
+		}
+
+		c.Lock()
+		defer c.Unlock()
+		c.ipv6Status = status
+		c.updateIPv6(c.ipv6)
+	}()
+}
+
+func (c *infoController) syncTime() {
+	status := "synced, skew is updated on the next fetch"
+	if err := c.clock.SetSystemTime(time.Now()); err != nil {
+		status = fmt.Sprintf("sync failed: %v", err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.status = status
+	c.updateTime(c.time)
+}
+
+func (c *infoController) refreshName() {
+	name, err := c.clock.RouterName()
+	if err != nil {
+		name = "-"
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.name = name
+	c.updateTime(c.time)
+}
+
+func (c *infoController) updateTime(t client.SystemTime) {
+	c.time = t
+	if c.timeRow == 0 {
+		return
+	}
+
+	routerTime := "-"
+	skew := "-"
+	if !t.Time.IsZero() {
+		routerTime = t.Time.Format("2006-01-02 15:04:05")
+		if loc, err := time.LoadLocation(t.Timezone); err == nil {
+			routerTime = t.Time.In(loc).Format("2006-01-02 15:04:05")
+		}
+		skew = formatSkew(t.Skew())
+	}
+	if c.status != "" {
+		skew += " (" + c.status + ")"
+	}
+
+	c.bodyTable.Rows[c.timeRow] = []string{
+		c.name,
+		routerTime,
+		skew,
+		fmt.Sprintf("%s / %s", t.Timezone, strings.Join(t.NTPServers, ", ")),
+	}
+}
+
+func (c *infoController) updateIPv6(info client.IPv6Info) {
+	c.ipv6 = info
+
+	mode := "[off](fg:red)"
+	if info.Enabled() {
+		state := "[down](fg:red)"
+		if info.Up {
+			state = "[up](fg:green)"
+		}
+		mode = fmt.Sprintf("%s %s", strings.ToUpper(info.Mode), state)
+	}
+	if c.ipv6Status != "" {
+		mode += " (" + c.ipv6Status + ")"
+	}
+
+	c.bodyTable.Rows[c.ipv6Row] = []string{
+		mode,
+		valueOrDash(info.Prefix),
+		valueOrDash(strings.Join(info.WANAddress, ", ")),
+		valueOrDash(strings.Join(info.LANAddress, ", ")),
+	}
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatSkew returns router clock skew colored by its size.
+func formatSkew(skew time.Duration) string {
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+
+	switch {
+	case abs < clockSkewTolerance:
+		return "[in sync](fg:green)"
+	case skew > 0:
+		return fmt.Sprintf("[%s ahead](fg:%s)", abs.Truncate(time.Second), skewColor(abs))
+	default:
+		return fmt.Sprintf("[%s behind](fg:%s)", abs.Truncate(time.Second), skewColor(abs))
+	}
+}
+
+func skewColor(abs time.Duration) string {
+	if abs < time.Minute {
+		return "yellow"
 	}
+	return "red"
 }
 
 func (c *infoController) subscribe(ctx context.Context) {
@@ -62,7 +387,29 @@ func (c *infoController) subscribe(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case s := <-c.streamStat:
-				c.update(s)
+				c.Lock()
+				c.stat = s
+				c.update()
+				c.Unlock()
+			case r := <-c.streamROM:
+				c.Lock()
+				c.rom = r
+				c.update()
+				c.Unlock()
+			case w := <-c.streamWAN:
+				c.Lock()
+				c.updateWAN(w)
+				c.Unlock()
+			case t := <-c.streamTime:
+				c.Lock()
+				c.status = ""
+				c.updateTime(t)
+				c.Unlock()
+			case i := <-c.streamIPv6:
+				c.Lock()
+				c.ipv6Status = ""
+				c.updateIPv6(i)
+				c.Unlock()
 			}
 		}
 	})
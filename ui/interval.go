@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+// IntervalIndicator shows effective polling interval in the top right
+// corner of the screen over the controller border.
+type IntervalIndicator struct {
+	*ui.Block
+	interval fmt.Stringer
+}
+
+// NewIntervalIndicator creates and returns polling interval indicator.
+func NewIntervalIndicator(interval fmt.Stringer) *IntervalIndicator {
+	return &IntervalIndicator{
+		Block:    ui.NewBlock(),
+		interval: interval,
+	}
+}
+
+// Resize updates indicator size.
+func (i *IntervalIndicator) Resize() {
+	w, _ := ui.TerminalDimensions()
+	i.SetRect(0, 0, w, 1)
+}
+
+func (i *IntervalIndicator) Draw(buf *ui.Buffer) {
+	text := fmt.Sprintf(" every %s [+/-/*] ", i.interval)
+	x := i.Max.X - len([]rune(text)) - 2
+	if x < i.Min.X {
+		return
+	}
+	buf.SetString(text, ui.NewStyle(ui.ColorYellow), image.Pt(x, i.Min.Y))
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+const (
+	// lanReconnectAttempts is a number of attempts to reach the router
+	// at the new LAN address while it restarts network.
+	lanReconnectAttempts = 12
+	// lanReconnectDelay is a delay between reconnection attempts.
+	lanReconnectDelay = 5 * time.Second
+)
+
+// LANManager reads and changes LAN settings, Reconnect moves the session
+// to the router at the new LAN address.
+type LANManager interface {
+	LANConfig() (client.LANConfig, error)
+	SetLANConfig(cfg client.LANConfig) error
+	Reconnect(ip string) error
+}
+
+// NewLANController creates and returns LAN settings UI controller.
+func NewLANController(manager LANManager) *lanController {
+	return &lanController{
+		Grid:     ui.NewGrid(),
+		bodyText: widgets.NewParagraph(),
+		footText: widgets.NewParagraph(),
+		lanForm: newForm("LAN settings",
+			"Router IP", "Netmask", "DHCP server (on/off)", "DHCP pool start", "DHCP pool end", "Lease time"),
+		manager: manager,
+	}
+}
+
+type lanController struct {
+	*ui.Grid
+
+	bodyText *widgets.Paragraph
+	footText *widgets.Paragraph
+	lanForm  *form
+
+	manager LANManager
+
+	cfg     client.LANConfig
+	pending *client.LANConfig
+	status  string
+
+	// refreshMu serializes settings loading, so older settings never
+	// replace newer ones
+	refreshMu sync.Mutex
+}
+
+func (c *lanController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+	c.lanForm.Resize(c.GetRect())
+}
+
+func (c *lanController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	c.lanForm.Draw(buf)
+}
+
+func (c *lanController) Init(ctx context.Context) {
+	c.initUI()
+	go c.refresh()
+}
+
+func (c *lanController) initUI() {
+	c.bodyText.Title = "LAN settings"
+	c.bodyText.PaddingLeft = 1
+
+	c.footText.Border = false
+	c.status = "Loading settings..."
+	c.update()
+
+	c.Grid.Set(
+		ui.NewRow(.8, c.bodyText),
+		ui.NewRow(.2, c.footText),
+	)
+}
+
+func (c *lanController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.lanForm.Handle(e) {
+		return true
+	}
+
+	if c.pending != nil {
+		cfg := *c.pending
+		c.pending = nil
+		c.status = ""
+		if e.ID == "y" {
+			c.status = "Applying settings..."
+			go c.apply(cfg, c.cfg.IP)
+		}
+		c.update()
+		return true
+	}
+
+	switch e.ID {
+	case "e":
+		dhcp := "off"
+		if c.cfg.DHCPEnabled {
+			dhcp = "on"
+		}
+		c.lanForm.Open(c.save, c.cfg.IP, c.cfg.Mask, dhcp, c.cfg.PoolStart, c.cfg.PoolEnd, c.cfg.LeaseTime)
+	case "r":
+		go c.refresh()
+	default:
+		return false
+	}
+
+	return true
+}
+
+// save validates form values and applies them, changing of the router
+// address requires confirmation since it drops all LAN connections.
+func (c *lanController) save(values []string) {
+	cfg := client.LANConfig{
+		IP:        strings.TrimSpace(values[0]),
+		Mask:      strings.TrimSpace(values[1]),
+		PoolStart: strings.TrimSpace(values[3]),
+		PoolEnd:   strings.TrimSpace(values[4]),
+		LeaseTime: strings.TrimSpace(values[5]),
+	}
+
+	switch strings.ToLower(strings.TrimSpace(values[2])) {
+	case "on":
+		cfg.DHCPEnabled = true
+	case "off":
+	default:
+		c.status = fmt.Sprintf("Invalid DHCP server state: %q", values[2])
+		c.update()
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		c.status = err.Error()
+		c.update()
+		return
+	}
+
+	if cfg.IP != c.cfg.IP || cfg.Mask != c.cfg.Mask {
+		c.pending = &cfg
+		c.status = fmt.Sprintf(
+			"[Router moves to %s/%s, all LAN devices lose connection until they renew DHCP lease. Apply?](fg:yellow) [y/N]",
+			cfg.IP, cfg.Mask,
+		)
+		c.update()
+		return
+	}
+
+	c.status = "Applying settings..."
+	c.update()
+	go c.apply(cfg, c.cfg.IP)
+}
+
+// apply saves settings, currentIP is a router address before the change.
+func (c *lanController) apply(cfg client.LANConfig, currentIP string) {
+	if err := c.manager.SetLANConfig(cfg); err != nil {
+		c.setStatus(fmt.Sprintf("Failed: %v", err))
+		return
+	}
+
+	if cfg.IP != currentIP {
+		c.reconnect(cfg.IP)
+	} else {
+		c.setStatus("LAN settings are saved")
+	}
+	c.refresh()
+}
+
+// reconnect waits for the router to come up at the new address.
+func (c *lanController) reconnect(ip string) {
+	var err error
+	for i := 1; i <= lanReconnectAttempts; i++ {
+		c.setStatus(fmt.Sprintf("Reconnecting to %s (attempt %d/%d)...", ip, i, lanReconnectAttempts))
+
+		time.Sleep(lanReconnectDelay)
+		if err = c.manager.Reconnect(ip); err == nil {
+			c.setStatus(fmt.Sprintf("Reconnected to the router at %s", ip))
+			return
+		}
+	}
+	c.setStatus(fmt.Sprintf("Failed to reconnect to %s: %v", ip, err))
+}
+
+func (c *lanController) refresh() {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	cfg, err := c.manager.LANConfig()
+	if err != nil {
+		c.setStatus(fmt.Sprintf("Failed to load settings: %v", err))
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.cfg = cfg
+	if c.status == "Loading settings..." {
+		c.status = ""
+	}
+	c.update()
+}
+
+func (c *lanController) setStatus(status string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.status = status
+	c.update()
+}
+
+func (c *lanController) update() {
+	dhcp := "[off](fg:red)"
+	if c.cfg.DHCPEnabled {
+		dhcp = "[on](fg:green)"
+	}
+
+	c.bodyText.Text = fmt.Sprintf(
+		"Router IP:    %s\nNetmask:      %s\n\nDHCP server:  %s\nDHCP pool:    %s - %s\nLease time:   %s",
+		c.cfg.IP, c.cfg.Mask, dhcp, c.cfg.PoolStart, c.cfg.PoolEnd, c.cfg.LeaseTime,
+	)
+
+	c.footText.Text = fmt.Sprintf("[e] edit  [r] reload\n%s", c.status)
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+// NewLogsController creates and returns system log UI controller.
+func NewLogsController(streamLog StreamLogRead) *logsController {
+	return &logsController{
+		Grid:       ui.NewGrid(),
+		bodyList:   widgets.NewList(),
+		footText:   widgets.NewParagraph(),
+		searchForm: newForm("Search", "Text"),
+		streamLog:  streamLog,
+		follow:     true,
+	}
+}
+
+type logsController struct {
+	*ui.Grid
+
+	bodyList   *widgets.List
+	footText   *widgets.Paragraph
+	searchForm *form
+
+	streamLog StreamLogRead
+
+	entries []client.LogEntry
+	search  string
+	follow  bool
+
+	once sync.Once
+}
+
+func (c *logsController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+	c.searchForm.Resize(c.GetRect())
+}
+
+func (c *logsController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	c.searchForm.Draw(buf)
+}
+
+func (c *logsController) Init(ctx context.Context) {
+	c.initUI()
+	go c.subscribe(ctx)
+}
+
+func (c *logsController) initUI() {
+	c.bodyList.Title = "System log"
+	c.bodyList.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorWhite)
+
+	c.footText.Border = false
+	c.updateFooter(0)
+
+	c.Grid.Set(
+		ui.NewRow(.9, c.bodyList),
+		ui.NewRow(.1, c.footText),
+	)
+}
+
+func (c *logsController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.searchForm.Handle(e) {
+		return true
+	}
+
+	switch e.ID {
+	case "<Up>", "k":
+		c.follow = false
+		c.bodyList.ScrollUp()
+	case "<Down>", "j":
+		c.bodyList.ScrollDown()
+	case "<PageUp>", "<C-b>":
+		c.follow = false
+		c.bodyList.ScrollPageUp()
+	case "<PageDown>", "<C-f>":
+		c.bodyList.ScrollPageDown()
+	case "g", "<Home>":
+		c.follow = false
+		c.bodyList.ScrollTop()
+	case "G", "<End>":
+		c.bodyList.ScrollBottom()
+	case "f":
+		c.follow = !c.follow
+		c.update()
+	case "/":
+		c.searchForm.Open(func(values []string) {
+			c.search = strings.TrimSpace(values[0])
+			c.update()
+		}, c.search)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *logsController) update() {
+	rows := make([]string, 0, len(c.entries))
+	search := strings.ToLower(c.search)
+
+	for _, entry := range c.entries {
+		if search != "" && !strings.Contains(strings.ToLower(entry.Message), search) {
+			continue
+		}
+		rows = append(rows, formatLogEntry(entry))
+	}
+
+	c.bodyList.Rows = rows
+	if c.follow && len(rows) > 0 {
+		c.bodyList.SelectedRow = len(rows) - 1
+	}
+	if c.bodyList.SelectedRow >= len(rows) {
+		c.bodyList.SelectedRow = 0
+	}
+
+	c.updateFooter(len(rows))
+}
+
+func (c *logsController) updateFooter(count int) {
+	follow := "off"
+	if c.follow {
+		follow = "on"
+	}
+	search := ""
+	if c.search != "" {
+		search = fmt.Sprintf(" | Search: %q", c.search)
+	}
+
+	c.footText.Text = fmt.Sprintf(
+		"Records: %d | Follow: %s%s | [/] search  [f] follow  [g/G] top/bottom",
+		count, follow, search,
+	)
+}
+
+func (c *logsController) subscribe(ctx context.Context) {
+	c.once.Do(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entries := <-c.streamLog:
+				c.Lock()
+				c.entries = entries
+				c.update()
+				c.Unlock()
+			}
+		}
+	})
+}
+
+// formatLogEntry returns log entry colored by its severity.
+func formatLogEntry(entry client.LogEntry) string {
+	if entry.Severity == "" {
+		return entry.Message
+	}
+
+	var color string
+	switch entry.Severity {
+	case "emerg", "alert", "crit", "err":
+		color = "red"
+	case "warn", "warning":
+		color = "yellow"
+	case "notice":
+		color = "cyan"
+	case "debug":
+		color = "blue"
+	default:
+		color = "white"
+	}
+
+	return fmt.Sprintf("[%s %s.%s](fg:%s) %s", entry.Time, entry.Facility, entry.Severity, color, entry.Message)
+}
@@ -3,7 +3,9 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
@@ -12,8 +14,30 @@ import (
 	"miwifi-termui/humanize"
 )
 
+// VPNSwitcher connects and disconnects router VPN client.
+type VPNSwitcher interface {
+	ConnectVPN(id string) error
+	DisconnectVPN() error
+}
+
+// NETOptions are optional network status UI controller sections, a section
+// is hidden when its stream is nil.
+type NETOptions struct {
+	// StreamVPN shows VPN state.
+	StreamVPN StreamVPNRead
+	// Switcher enables VPN connect and disconnect.
+	Switcher VPNSwitcher
+	// StreamIPv6 shows IPv6 state.
+	StreamIPv6 StreamIPv6Read
+}
+
 // NewNETController creates and returns network status UI controller.
-func NewNETController(streamStat StreamStatRead, streamBand StreamBandRead) *netController {
+func NewNETController(
+	streamStat StreamStatRead,
+	streamBand StreamBandRead,
+	streamWAN StreamWANRead,
+	opts NETOptions,
+) *netController {
 	return &netController{
 		Grid:       ui.NewGrid(),
 		headText:   widgets.NewParagraph(),
@@ -21,6 +45,10 @@ func NewNETController(streamStat StreamStatRead, streamBand StreamBandRead) *net
 		footText:   widgets.NewParagraph(),
 		streamStat: streamStat,
 		streamBand: streamBand,
+		streamWAN:  streamWAN,
+		streamVPN:  opts.StreamVPN,
+		switcher:   opts.Switcher,
+		streamIPv6: opts.StreamIPv6,
 	}
 }
 
@@ -33,6 +61,16 @@ type netController struct {
 
 	streamStat StreamStatRead
 	streamBand StreamBandRead
+	streamWAN  StreamWANRead
+	streamVPN  StreamVPNRead
+	switcher   VPNSwitcher
+	streamIPv6 StreamIPv6Read
+	wan        wanTracker
+	vpn        client.VPN
+	ipv6       client.IPv6Info
+	profile    int
+	status     string
+	prefilled  bool
 	once       sync.Once
 }
 
@@ -74,6 +112,16 @@ func (c *netController) update(s client.Stat, b client.Band) {
 		humanize.Bytes(s.WAN.DownSpeed),
 		humanize.Bytes(s.WAN.UpSpeed),
 	)
+	if c.streamWAN != nil {
+		c.headText.Text += "\n" + c.wan.summary()
+	}
+	if c.streamIPv6 != nil {
+		c.headText.Text += "\n" + c.ipv6Summary()
+	}
+
+	if !c.prefilled {
+		c.prefill(s.WAN.History)
+	}
 
 	if len(c.bodyPlot.Data[0]) >= c.bodyPlot.Dx() {
 		c.bodyPlot.Data[0] = c.bodyPlot.Data[0][1:]
@@ -90,6 +138,134 @@ func (c *netController) update(s client.Stat, b client.Band) {
 		b.Bandwidth,
 		humanize.Bytes(s.WAN.MaxDownloadSpeed),
 	)
+	if c.streamVPN != nil {
+		c.footText.Text += "\n" + c.vpnSummary()
+	}
+}
+
+func (c *netController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.switcher == nil || len(c.vpn.Profiles) == 0 {
+		return false
+	}
+
+	switch e.ID {
+	case "p":
+		c.profile = (c.profile + 1) % len(c.vpn.Profiles)
+	case "v":
+		connected := c.vpn.Status.Status != client.VPNDisconnected
+		profile := c.selectedProfile()
+		go c.switchVPN(connected, profile)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// switchVPN disconnects VPN when it's connected and connects the profile
+// otherwise, state is updated on the next fetch.
+func (c *netController) switchVPN(connected bool, profile client.VPNProfile) {
+	var err error
+	if connected {
+		err = c.switcher.DisconnectVPN()
+	} else {
+		err = c.switcher.ConnectVPN(profile.ID)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.status = ""
+	if err != nil {
+		c.status = fmt.Sprintf("VPN switch failed: %v", err)
+	}
+}
+
+// selectedProfile returns the connected profile or the one picked by user,
+// profiles list must not be empty.
+func (c *netController) selectedProfile() client.VPNProfile {
+	if p, ok := c.vpn.Profile(c.vpn.Status.ProfileID); ok && c.vpn.Status.Status != client.VPNDisconnected {
+		return p
+	}
+	if c.profile < 0 || c.profile >= len(c.vpn.Profiles) {
+		c.profile = 0
+	}
+	return c.vpn.Profiles[c.profile]
+}
+
+// ipv6Summary returns one line IPv6 state description.
+func (c *netController) ipv6Summary() string {
+	if !c.ipv6.Enabled() {
+		return "IPv6: [off](fg:red)"
+	}
+
+	state := "[down](fg:red)"
+	if c.ipv6.Up {
+		state = "[up](fg:green)"
+	}
+	return fmt.Sprintf(
+		"IPv6: %s %s | Prefix: %s | WAN: %s | LAN: %s | DNS: %s",
+		strings.ToUpper(c.ipv6.Mode), state,
+		valueOrDash(c.ipv6.Prefix),
+		valueOrDash(strings.Join(c.ipv6.WANAddress, ", ")),
+		valueOrDash(strings.Join(c.ipv6.LANAddress, ", ")),
+		valueOrDash(strings.Join(c.ipv6.DNS, ", ")),
+	)
+}
+
+// vpnSummary returns one line VPN state description.
+func (c *netController) vpnSummary() string {
+	if len(c.vpn.Profiles) == 0 {
+		return "VPN: no profiles"
+	}
+
+	profile := c.selectedProfile()
+	st := c.vpn.Status
+	text := fmt.Sprintf("VPN: %s (%s %s) ", profile.Name, strings.ToUpper(profile.Proto), profile.Server)
+
+	switch st.Status {
+	case client.VPNConnected:
+		route := "only VPN subnets go through tunnel"
+		if st.AllTraffic {
+			route = "all traffic goes through tunnel"
+		}
+		text += fmt.Sprintf("[connected](fg:green) %s, IP %s | %s", st.UpTime.Truncate(time.Second), st.IP, route)
+	case client.VPNConnecting:
+		text += "[connecting](fg:yellow) | traffic goes directly"
+	default:
+		text += "[disconnected](fg:red) | traffic goes directly"
+	}
+
+	if c.switcher != nil {
+		text += " | [v] connect/disconnect  [p] next profile"
+	}
+	if c.status != "" {
+		text += " | " + c.status
+	}
+	return text
+}
+
+// prefill fills plot with the router traffic history, upstream history
+// is not provided by the router so it is padded with zeros.
+func (c *netController) prefill(history client.History) {
+	if len(history) == 0 {
+		return
+	}
+	c.prefilled = true
+
+	if w := c.bodyPlot.Dx(); w > 0 && len(history) >= w {
+		history = history[len(history)-w+1:]
+	}
+
+	down := make([]float64, len(history))
+	for i, v := range history {
+		down[i] = float64(v)
+	}
+	c.bodyPlot.Data[0] = down
+	c.bodyPlot.Data[1] = make([]float64, len(history))
 }
 
 func (c *netController) subscribe(ctx context.Context) {
@@ -100,8 +276,22 @@ func (c *netController) subscribe(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case b = <-c.streamBand:
+			case w := <-c.streamWAN:
+				c.Lock()
+				c.wan.update(w)
+				c.Unlock()
+			case v := <-c.streamVPN:
+				c.Lock()
+				c.vpn = v
+				c.Unlock()
+			case i := <-c.streamIPv6:
+				c.Lock()
+				c.ipv6 = i
+				c.Unlock()
 			case s := <-c.streamStat:
+				c.Lock()
 				c.update(s, b)
+				c.Unlock()
 			}
 		}
 	})
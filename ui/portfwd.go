@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+// PortForwarder manages port forwarding rules.
+type PortForwarder interface {
+	PortForwards() ([]client.PortForward, error)
+	PortRangeForwards() ([]client.PortRangeForward, error)
+	AddPortForward(rule client.PortForward) error
+	AddPortRangeForward(rule client.PortRangeForward) error
+	UpdatePortForward(old, rule client.PortForwardRule) error
+	DeletePortForward(port int, proto client.Protocol) error
+}
+
+// NewPortFwdController creates and returns port forwarding rules UI controller.
+func NewPortFwdController(forwarder PortForwarder) *portFwdController {
+	return &portFwdController{
+		Grid:      ui.NewGrid(),
+		bodyTable: newSelectTable(),
+		footText:  widgets.NewParagraph(),
+		ruleForm: newForm("Port forwarding rule",
+			"Name", "Protocol (tcp/udp/both)", "External port or range", "Internal IP", "Internal port"),
+		forwarder: forwarder,
+	}
+}
+
+type portFwdController struct {
+	*ui.Grid
+
+	bodyTable *selectTable
+	footText  *widgets.Paragraph
+	ruleForm  *form
+
+	forwarder PortForwarder
+
+	rules []portFwdRule
+	// pending is a rule which removal awaits confirmation
+	pending *portFwdRule
+	status  string
+
+	// refreshMu serializes rules loading, so older rules never replace
+	// newer ones
+	refreshMu sync.Mutex
+}
+
+// portFwdRule is a single port or ports range forwarding rule,
+// single port rules have equal from and to ports.
+type portFwdRule struct {
+	name     string
+	proto    client.Protocol
+	fromPort int
+	toPort   int
+	destIP   string
+	destPort int
+}
+
+func (r portFwdRule) ports() string {
+	if r.fromPort == r.toPort {
+		return strconv.Itoa(r.fromPort)
+	}
+	return fmt.Sprintf("%d-%d", r.fromPort, r.toPort)
+}
+
+// clientRule returns API rule of the same kind.
+func (r portFwdRule) clientRule() client.PortForwardRule {
+	if r.fromPort == r.toPort {
+		return client.PortForward{
+			Name:     r.name,
+			Proto:    r.proto,
+			Port:     r.fromPort,
+			DestIP:   r.destIP,
+			DestPort: r.destPort,
+		}
+	}
+	return client.PortRangeForward{
+		Name:     r.name,
+		Proto:    r.proto,
+		FromPort: r.fromPort,
+		ToPort:   r.toPort,
+		DestIP:   r.destIP,
+	}
+}
+
+func (r portFwdRule) values() []string {
+	destPort := ""
+	if r.fromPort == r.toPort {
+		destPort = strconv.Itoa(r.destPort)
+	}
+	return []string{r.name, r.proto.String(), r.ports(), r.destIP, destPort}
+}
+
+func (c *portFwdController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+	c.ruleForm.Resize(c.GetRect())
+}
+
+func (c *portFwdController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	c.ruleForm.Draw(buf)
+}
+
+func (c *portFwdController) Init(ctx context.Context) {
+	c.initUI()
+	go c.refresh()
+}
+
+func (c *portFwdController) initUI() {
+	c.bodyTable.Title = "Port forwarding"
+	c.bodyTable.Rows = [][]string{{"Name", "Protocol", "External port", "Internal IP", "Internal port"}}
+
+	c.footText.Border = false
+	c.status = "Loading rules..."
+	c.updateFooter()
+
+	c.Grid.Set(
+		ui.NewRow(.8, c.bodyTable),
+		ui.NewRow(.2, c.footText),
+	)
+}
+
+func (c *portFwdController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.ruleForm.Handle(e) {
+		return true
+	}
+
+	if c.pending != nil {
+		rule := *c.pending
+		c.pending = nil
+		c.status = ""
+		if e.ID == "y" {
+			go c.apply(func() error { return c.delete(rule) }, "Rule "+rule.name+" is removed")
+		}
+		c.updateFooter()
+		return true
+	}
+
+	if c.bodyTable.Handle(e) {
+		return true
+	}
+
+	switch e.ID {
+	case "a":
+		c.ruleForm.Open(func(values []string) { c.save(nil, values) }, "", "tcp")
+	case "e":
+		rule, ok := c.selectedRule()
+		if !ok {
+			return false
+		}
+		c.ruleForm.Open(func(values []string) { c.save(&rule, values) }, rule.values()...)
+	case "d":
+		rule, ok := c.selectedRule()
+		if !ok {
+			return false
+		}
+		c.pending = &rule
+		c.status = fmt.Sprintf("Remove rule %s? [y/N]", rule.name)
+		c.updateFooter()
+	case "r":
+		go c.refresh()
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *portFwdController) selectedRule() (portFwdRule, bool) {
+	i := c.bodyTable.SelectedRow
+	if i < 0 || i >= len(c.rules) {
+		return portFwdRule{}, false
+	}
+	return c.rules[i], true
+}
+
+// save validates form values and replaces old rule with a new one,
+// old rule is nil for a new rule.
+func (c *portFwdController) save(old *portFwdRule, values []string) {
+	rule, err := parsePortFwdRule(values)
+	if err != nil {
+		c.status = err.Error()
+		c.updateFooter()
+		return
+	}
+
+	go c.apply(func() error {
+		if old != nil {
+			return c.forwarder.UpdatePortForward(old.clientRule(), rule.clientRule())
+		}
+		switch r := rule.clientRule().(type) {
+		case client.PortForward:
+			return c.forwarder.AddPortForward(r)
+		case client.PortRangeForward:
+			return c.forwarder.AddPortRangeForward(r)
+		}
+		return nil
+	}, "Rule "+rule.name+" is saved")
+}
+
+func (c *portFwdController) delete(rule portFwdRule) error {
+	return c.forwarder.DeletePortForward(rule.fromPort, rule.proto)
+}
+
+// apply runs action in background and reloads rules, controller state is
+// changed under its lock only as Draw and Handle read it concurrently.
+func (c *portFwdController) apply(action func() error, done string) {
+	status := done
+	if err := action(); err != nil {
+		status = fmt.Sprintf("Failed: %v", err)
+	}
+
+	c.Lock()
+	c.status = status
+	c.updateFooter()
+	c.Unlock()
+
+	c.refresh()
+}
+
+func (c *portFwdController) refresh() {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	singles, err := c.forwarder.PortForwards()
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	ranges, err := c.forwarder.PortRangeForwards()
+	if err != nil {
+		c.fail(err)
+		return
+	}
+
+	rules := make([]portFwdRule, 0, len(singles)+len(ranges))
+	for _, r := range singles {
+		rules = append(rules, portFwdRule{
+			name:     r.Name,
+			proto:    r.Proto,
+			fromPort: r.Port,
+			toPort:   r.Port,
+			destIP:   r.DestIP,
+			destPort: r.DestPort,
+		})
+	}
+	for _, r := range ranges {
+		rules = append(rules, portFwdRule{
+			name:     r.Name,
+			proto:    r.Proto,
+			fromPort: r.FromPort,
+			toPort:   r.ToPort,
+			destIP:   r.DestIP,
+		})
+	}
+
+	c.Lock()
+	c.update(rules)
+	c.Unlock()
+}
+
+func (c *portFwdController) fail(err error) {
+	c.Lock()
+	c.status = fmt.Sprintf("Failed to load rules: %v", err)
+	c.updateFooter()
+	c.Unlock()
+}
+
+func (c *portFwdController) update(rules []portFwdRule) {
+	c.rules = rules
+
+	c.bodyTable.Rows = c.bodyTable.Rows[:1]
+	for _, r := range rules {
+		destPort := "-"
+		if r.fromPort == r.toPort {
+			destPort = strconv.Itoa(r.destPort)
+		}
+		c.bodyTable.Rows = append(c.bodyTable.Rows, []string{
+			r.name,
+			r.proto.String(),
+			r.ports(),
+			r.destIP,
+			destPort,
+		})
+	}
+
+	if c.status == "Loading rules..." {
+		c.status = ""
+	}
+	c.updateFooter()
+}
+
+func (c *portFwdController) updateFooter() {
+	c.footText.Text = fmt.Sprintf(
+		"Rules: %d | [a] add  [e] edit  [d] remove  [r] reload\n%s",
+		len(c.rules),
+		c.status,
+	)
+}
+
+func parsePortFwdRule(values []string) (portFwdRule, error) {
+	var rule portFwdRule
+
+	rule.name = values[0]
+	if rule.name == "" {
+		return rule, errors.New("rule name is required")
+	}
+
+	proto, err := client.ParseProtocol(values[1])
+	if err != nil {
+		return rule, err
+	}
+	rule.proto = proto
+
+	rule.fromPort, rule.toPort, err = client.ParsePorts(values[2])
+	if err != nil {
+		return rule, err
+	}
+
+	rule.destIP = values[3]
+	if net.ParseIP(rule.destIP).To4() == nil {
+		return rule, fmt.Errorf("invalid internal IP: %q", rule.destIP)
+	}
+
+	if rule.fromPort == rule.toPort {
+		rule.destPort = rule.fromPort
+		if values[4] != "" {
+			if rule.destPort, err = client.ParsePort(values[4]); err != nil {
+				return rule, err
+			}
+		}
+	}
+
+	return rule, nil
+}
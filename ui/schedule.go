@@ -0,0 +1,391 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+var weekdayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// ParentalControl manages devices internet access schedules and URL filters.
+type ParentalControl interface {
+	AccessRules(mac string) ([]client.AccessRule, error)
+	AddAccessRule(rule client.AccessRule) error
+	DeleteAccessRule(mac, id string) error
+	URLFilter(mac string) (client.URLFilter, error)
+	SetURLFilter(mac string, filter client.URLFilter) error
+}
+
+// newScheduleEditor creates and returns modal device access schedule editor.
+func newScheduleEditor(parental ParentalControl) *scheduleEditor {
+	e := &scheduleEditor{
+		Paragraph:  widgets.NewParagraph(),
+		parental:   parental,
+		ruleForm:   newForm("Block internet access", "Days (1-7, e.g. 1-5)", "From (HH:MM)", "To (HH:MM)"),
+		filterForm: newForm("URL filter", "Mode (black/white)", "URLs (comma separated)"),
+	}
+	e.PaddingLeft = 1
+	e.BorderStyle.Fg = ui.ColorYellow
+	return e
+}
+
+// scheduleEditor is a modal editor of the device weekly internet access
+// schedule and URL filter drawn over a UI controller.
+type scheduleEditor struct {
+	*widgets.Paragraph
+
+	parental   ParentalControl
+	ruleForm   *form
+	filterForm *form
+
+	device   client.DeviceStat
+	rules    []client.AccessRule
+	filter   *client.URLFilter
+	selected int
+	confirm  bool
+	active   bool
+	status   string
+
+	// refreshMu serializes rules loading, so older rules never replace
+	// newer ones
+	refreshMu sync.Mutex
+}
+
+// Open shows editor of the device schedule and loads its rules.
+func (e *scheduleEditor) Open(device client.DeviceStat) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.device = device
+	e.rules = nil
+	e.filter = nil
+	e.selected = 0
+	e.confirm = false
+	e.active = true
+	e.status = "Loading..."
+	go e.refresh("")
+}
+
+// Active reports whether editor is shown.
+func (e *scheduleEditor) Active() bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.active
+}
+
+// Resize places editor over the given area.
+func (e *scheduleEditor) Resize(area image.Rectangle) {
+	e.SetRect(area.Min.X+2, area.Min.Y+1, area.Max.X-2, area.Max.Y-1)
+	e.ruleForm.Resize(area)
+	e.filterForm.Resize(area)
+}
+
+// Handle processes keyboard input when editor is active.
+func (e *scheduleEditor) Handle(ev ui.Event) bool {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.ruleForm.Handle(ev) || e.filterForm.Handle(ev) {
+		return true
+	}
+	if !e.active || ev.Type != ui.KeyboardEvent {
+		return false
+	}
+
+	if e.confirm {
+		e.confirm = false
+		e.status = ""
+		if ev.ID == "y" && e.selected < len(e.rules) {
+			e.status = "Deleting..."
+			go e.deleteRule(e.device.Mac, e.rules[e.selected])
+		}
+		return true
+	}
+
+	switch ev.ID {
+	case "<Escape>", "q":
+		e.active = false
+	case "<Down>", "j":
+		if e.selected < len(e.rules)-1 {
+			e.selected++
+		}
+	case "<Up>", "k":
+		if e.selected > 0 {
+			e.selected--
+		}
+	case "a":
+		e.ruleForm.Open(e.addRule, "1-5", "22:00", "07:00")
+	case "d":
+		if e.selected < len(e.rules) {
+			e.confirm = true
+			e.status = fmt.Sprintf("Delete rule %s? [y/N]", formatAccessRule(e.rules[e.selected]))
+		}
+	case "u":
+		if e.filter == nil {
+			e.status = "URL filter is not supported by the router"
+			break
+		}
+		e.filterForm.Open(e.setFilter, e.filter.Mode, strings.Join(e.filter.URLs, ","))
+	case "r":
+		go e.refresh("")
+	}
+
+	return true
+}
+
+// refresh loads rules and URL filter of the device, status is shown when
+// they are loaded.
+func (e *scheduleEditor) refresh(status string) {
+	e.refreshMu.Lock()
+	defer e.refreshMu.Unlock()
+
+	e.Lock()
+	mac := e.device.Mac
+	e.Unlock()
+
+	rules, err := e.parental.AccessRules(mac)
+	if err != nil {
+		e.setStatus(mac, fmt.Sprintf("Failed to load schedule: %v", err))
+		return
+	}
+	filter, filterErr := e.parental.URLFilter(mac)
+
+	e.Lock()
+	defer e.Unlock()
+
+	// editor may be reopened for another device while loading
+	if e.device.Mac != mac {
+		return
+	}
+
+	e.rules = rules
+	if e.selected >= len(rules) {
+		e.selected = 0
+	}
+	e.status = status
+
+	e.filter = nil
+	if filterErr != nil {
+		var apiErr *client.APIError
+		if !errors.As(filterErr, &apiErr) {
+			e.status = fmt.Sprintf("Failed to load URL filter: %v", filterErr)
+		}
+		return
+	}
+	e.filter = &filter
+}
+
+// setStatus shows status if editor is still opened for the device.
+func (e *scheduleEditor) setStatus(mac, status string) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.device.Mac == mac {
+		e.status = status
+	}
+}
+
+func (e *scheduleEditor) addRule(values []string) {
+	days, err := client.ParseWeekdays(values[0])
+	if err != nil {
+		e.status = err.Error()
+		return
+	}
+	for _, v := range values[1:] {
+		if _, err := time.Parse("15:04", v); err != nil {
+			e.status = fmt.Sprintf("Invalid time: %q", v)
+			return
+		}
+	}
+
+	rule := client.AccessRule{
+		Mac:      e.device.Mac,
+		Weekdays: days,
+		From:     values[1],
+		To:       values[2],
+		Enabled:  1,
+	}
+
+	go func() {
+		if err := e.parental.AddAccessRule(rule); err != nil {
+			e.setStatus(rule.Mac, fmt.Sprintf("Failed to add rule: %v", err))
+			return
+		}
+		e.refresh(fmt.Sprintf("Rule %s is added", formatAccessRule(rule)))
+	}()
+}
+
+func (e *scheduleEditor) deleteRule(mac string, rule client.AccessRule) {
+	if err := e.parental.DeleteAccessRule(mac, rule.ID); err != nil {
+		e.setStatus(mac, fmt.Sprintf("Failed to delete rule: %v", err))
+		return
+	}
+	e.refresh(fmt.Sprintf("Rule %s is deleted", formatAccessRule(rule)))
+}
+
+func (e *scheduleEditor) setFilter(values []string) {
+	mode := strings.TrimSpace(values[0])
+	if mode != "black" && mode != "white" {
+		e.status = fmt.Sprintf("Invalid URL filter mode: %q", mode)
+		return
+	}
+
+	filter := client.URLFilter{Mode: mode}
+	for _, u := range strings.Split(values[1], ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			filter.URLs = append(filter.URLs, u)
+		}
+	}
+
+	mac := e.device.Mac
+	go func() {
+		if err := e.parental.SetURLFilter(mac, filter); err != nil {
+			e.setStatus(mac, fmt.Sprintf("Failed to update URL filter: %v", err))
+			return
+		}
+
+		e.Lock()
+		defer e.Unlock()
+		if e.device.Mac == mac {
+			e.filter = &filter
+			e.status = "URL filter is updated"
+		}
+	}()
+}
+
+func (e *scheduleEditor) Draw(buf *ui.Buffer) {
+	e.Lock()
+	defer e.Unlock()
+
+	if !e.active {
+		return
+	}
+
+	var text strings.Builder
+
+	text.WriteString("    ")
+	for h := 0; h < 24; h++ {
+		fmt.Fprintf(&text, "%-3d", h)
+	}
+	text.WriteString("\n")
+	for day := 1; day <= 7; day++ {
+		text.WriteString(weekdayNames[day-1] + " ")
+		for h := 0; h < 24; h++ {
+			if scheduleBlocked(e.rules, day, h) {
+				text.WriteString("[██](fg:red) ")
+			} else {
+				text.WriteString("[··](fg:green) ")
+			}
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("\nRules:\n")
+	if len(e.rules) == 0 {
+		text.WriteString("  no rules, internet access is always allowed\n")
+	}
+	for i, rule := range e.rules {
+		line := formatAccessRule(rule)
+		if rule.Enabled == 0 {
+			line += " (disabled)"
+		}
+		if i == e.selected {
+			fmt.Fprintf(&text, "[> %s](fg:yellow)\n", line)
+		} else {
+			fmt.Fprintf(&text, "  %s\n", line)
+		}
+	}
+
+	text.WriteString("\nURL filter: ")
+	switch {
+	case e.filter == nil:
+		text.WriteString("not supported")
+	case len(e.filter.URLs) == 0:
+		text.WriteString("off")
+	case e.filter.Mode == "white":
+		text.WriteString("allow only " + strings.Join(e.filter.URLs, ", "))
+	default:
+		text.WriteString("block " + strings.Join(e.filter.URLs, ", "))
+	}
+
+	text.WriteString("\n\n[a] add rule  [d] delete rule  [u] URL filter  [r] reload  [Esc] close\n")
+	text.WriteString(e.status)
+
+	e.Title = fmt.Sprintf("Internet access schedule of %s", e.device.Name)
+	e.Text = text.String()
+
+	buf.Fill(ui.NewCell(' '), e.GetRect())
+	e.Paragraph.Draw(buf)
+	e.ruleForm.Draw(buf)
+	e.filterForm.Draw(buf)
+}
+
+// scheduleBlocked reports whether internet access is blocked by enabled
+// rules at any time within the hour of the week day.
+func scheduleBlocked(rules []client.AccessRule, day, hour int) bool {
+	slotFrom, slotTo := hour*60, hour*60+60
+
+	for _, rule := range rules {
+		if rule.Enabled == 0 {
+			continue
+		}
+		from, err := parseMinutes(rule.From)
+		if err != nil {
+			continue
+		}
+		to, err := parseMinutes(rule.To)
+		if err != nil {
+			continue
+		}
+
+		if from < to {
+			if rule.Weekdays.Contains(day) && from < slotTo && to > slotFrom {
+				return true
+			}
+			continue
+		}
+
+		// Window crosses midnight and continues on the next day
+		if rule.Weekdays.Contains(day) && from < slotTo {
+			return true
+		}
+		prev := day - 1
+		if prev == 0 {
+			prev = 7
+		}
+		if rule.Weekdays.Contains(prev) && to > slotFrom {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseMinutes returns minutes since midnight of "15:04" formatted time.
+func parseMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatAccessRule returns human readable representation of the rule.
+func formatAccessRule(rule client.AccessRule) string {
+	days := make([]string, 0, len(rule.Weekdays))
+	for _, d := range rule.Weekdays {
+		if d >= 1 && d <= 7 {
+			days = append(days, weekdayNames[d-1])
+		}
+	}
+	return fmt.Sprintf("%s %s-%s", strings.Join(days, ","), rule.From, rule.To)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// newSelectTable creates and returns table with selectable rows.
+func newSelectTable() *selectTable {
+	return &selectTable{
+		Table:            widgets.NewTable(),
+		SelectedRowStyle: ui.NewStyle(ui.ColorBlack, ui.ColorWhite),
+	}
+}
+
+// selectTable is a table which first row is a header and the rest of rows
+// can be selected.
+type selectTable struct {
+	*widgets.Table
+
+	SelectedRow      int
+	SelectedRowStyle ui.Style
+}
+
+// ScrollUp selects previous row.
+func (t *selectTable) ScrollUp() {
+	if t.SelectedRow > 0 {
+		t.SelectedRow--
+	}
+}
+
+// ScrollDown selects next row.
+func (t *selectTable) ScrollDown() {
+	if t.SelectedRow < len(t.Rows)-2 {
+		t.SelectedRow++
+	}
+}
+
+// Handle processes rows selection keys.
+func (t *selectTable) Handle(e ui.Event) bool {
+	switch e.ID {
+	case "<Up>", "k":
+		t.ScrollUp()
+	case "<Down>", "j":
+		t.ScrollDown()
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *selectTable) Draw(buf *ui.Buffer) {
+	if t.SelectedRow > len(t.Rows)-2 {
+		t.SelectedRow = len(t.Rows) - 2
+	}
+	if t.SelectedRow < 0 {
+		t.SelectedRow = 0
+	}
+
+	t.RowStyles = make(map[int]ui.Style)
+	if len(t.Rows) > 1 {
+		t.RowStyles[t.SelectedRow+1] = t.SelectedRowStyle
+	}
+	t.FillRow = true
+	t.Table.Draw(buf)
+}
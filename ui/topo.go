@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+// roamTTL is how long roamed clients stay highlighted.
+const roamTTL = time.Minute
+
+// NewTopoController creates and returns mesh topology UI controller.
+func NewTopoController(streamTopo StreamTopoRead) *topoController {
+	return &topoController{
+		Grid:       ui.NewGrid(),
+		bodyTree:   widgets.NewTree(),
+		footText:   widgets.NewParagraph(),
+		streamTopo: streamTopo,
+		collapsed:  make(map[string]bool),
+		parents:    make(map[string]string),
+		roamed:     make(map[string]time.Time),
+	}
+}
+
+type topoController struct {
+	*ui.Grid
+
+	bodyTree *widgets.Tree
+	footText *widgets.Paragraph
+
+	streamTopo StreamTopoRead
+
+	root      *widgets.TreeNode
+	collapsed map[string]bool      // collapsed nodes by MAC address
+	parents   map[string]string    // client MAC address to node MAC address
+	roamed    map[string]time.Time // clients roaming time by MAC address
+
+	once sync.Once
+}
+
+// topoValue is a tree node value with a node MAC address as a key.
+type topoValue struct {
+	key  string
+	text string
+}
+
+func (v topoValue) String() string {
+	return v.text
+}
+
+func (c *topoController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+}
+
+func (c *topoController) Init(ctx context.Context) {
+	c.initUI()
+	go c.subscribe(ctx)
+}
+
+func (c *topoController) initUI() {
+	c.bodyTree.Title = "Mesh topology"
+	c.bodyTree.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorWhite)
+
+	c.footText.Border = false
+	c.footText.Text = "[Enter] expand/collapse node  [E/C] expand/collapse all"
+
+	c.Grid.Set(
+		ui.NewRow(.9, c.bodyTree),
+		ui.NewRow(.1, c.footText),
+	)
+}
+
+func (c *topoController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	switch e.ID {
+	case "<Up>", "k":
+		c.bodyTree.ScrollUp()
+	case "<Down>", "j":
+		c.bodyTree.ScrollDown()
+	case "<Enter>", "<Space>":
+		node := c.bodyTree.SelectedNode()
+		if node == nil || len(node.Nodes) == 0 {
+			return false
+		}
+		c.bodyTree.ToggleExpand()
+		c.collapsed[node.Value.(topoValue).key] = !node.Expanded
+	case "E":
+		c.bodyTree.ExpandAll()
+		c.collapsed = make(map[string]bool)
+	case "C":
+		c.bodyTree.CollapseAll()
+		c.bodyTree.Walk(func(node *widgets.TreeNode) bool {
+			c.collapsed[node.Value.(topoValue).key] = true
+			return true
+		})
+	default:
+		return false
+	}
+	c.clampSelection()
+	return true
+}
+
+// clampSelection keeps selected row within visible rows after nodes change.
+func (c *topoController) clampSelection() {
+	if c.root != nil && c.bodyTree.SelectedRow >= visibleRows(c.root) {
+		c.bodyTree.ScrollBottom()
+	}
+}
+
+func (c *topoController) update(root client.TopoNode) {
+	now := time.Now()
+	parents := make(map[string]string)
+	c.walkClients(root, func(node client.TopoNode, cl client.TopoClient) {
+		parents[cl.Mac] = node.Mac
+		if parent, ok := c.parents[cl.Mac]; ok && parent != node.Mac {
+			c.roamed[cl.Mac] = now
+		}
+	})
+	c.parents = parents
+
+	c.root = c.treeNode(root, true)
+	c.bodyTree.SetNodes([]*widgets.TreeNode{c.root})
+	c.clampSelection()
+	c.footText.Text = fmt.Sprintf(
+		"Nodes: %d | Clients: %d | [Enter] expand/collapse node  [E/C] expand/collapse all",
+		countNodes(root), root.ClientCount(),
+	)
+}
+
+func (c *topoController) treeNode(node client.TopoNode, root bool) *widgets.TreeNode {
+	backhaul := node.Backhaul
+	if root {
+		backhaul = "main router"
+	} else if backhaul == "" {
+		backhaul = "unknown backhaul"
+	}
+
+	tn := &widgets.TreeNode{
+		Value: topoValue{
+			key: node.Mac,
+			text: fmt.Sprintf("[%s](fg:cyan) %s %s (%s) - %d clients",
+				node.Name, node.Hardware, node.IP, backhaul, node.ClientCount()),
+		},
+		Expanded: !c.collapsed[node.Mac],
+	}
+
+	for _, leaf := range node.Leafs {
+		tn.Nodes = append(tn.Nodes, c.treeNode(leaf, false))
+	}
+	for _, cl := range node.Clients {
+		text := fmt.Sprintf("%s %s %s", cl.Name, cl.IP, cl.Mac)
+		if roamedAt, ok := c.roamed[cl.Mac]; ok && time.Since(roamedAt) < roamTTL {
+			text = fmt.Sprintf("%s [roamed](fg:black,bg:yellow)", text)
+		}
+		tn.Nodes = append(tn.Nodes, &widgets.TreeNode{Value: topoValue{key: cl.Mac, text: text}})
+	}
+
+	return tn
+}
+
+// walkClients calls fn for each client of the node and its leafs.
+func (c *topoController) walkClients(node client.TopoNode, fn func(client.TopoNode, client.TopoClient)) {
+	for _, cl := range node.Clients {
+		fn(node, cl)
+	}
+	for _, leaf := range node.Leafs {
+		c.walkClients(leaf, fn)
+	}
+}
+
+func (c *topoController) subscribe(ctx context.Context) {
+	c.once.Do(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t := <-c.streamTopo:
+				c.Lock()
+				c.update(t)
+				c.Unlock()
+			}
+		}
+	})
+}
+
+func countNodes(node client.TopoNode) int {
+	count := 1
+	for _, leaf := range node.Leafs {
+		count += countNodes(leaf)
+	}
+	return count
+}
+
+// visibleRows returns number of tree rows shown for the node.
+func visibleRows(node *widgets.TreeNode) int {
+	rows := 1
+	if node.Expanded {
+		for _, n := range node.Nodes {
+			rows += visibleRows(n)
+		}
+	}
+	return rows
+}
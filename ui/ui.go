@@ -11,10 +11,30 @@ import (
 // Read streams to update UI controllers.
 type StreamStatRead <-chan client.Stat
 type StreamBandRead <-chan client.Band
+type StreamQoSRead <-chan client.QoS
+type StreamWANRead <-chan client.WANInfo
+type StreamROMRead <-chan client.ROMUpdate
+type StreamLogRead <-chan []client.LogEntry
+type StreamTopoRead <-chan client.TopoNode
+type StreamDevicesRead <-chan []client.DeviceInfo
+type StreamUPnPRead <-chan client.UPnP
+type StreamTimeRead <-chan client.SystemTime
+type StreamVPNRead <-chan client.VPN
+type StreamIPv6Read <-chan client.IPv6Info
 
 // Write streams to update UI controllers.
 type StreamStatWrite chan<- client.Stat
 type StreamBandWrite chan<- client.Band
+type StreamQoSWrite chan<- client.QoS
+type StreamWANWrite chan<- client.WANInfo
+type StreamROMWrite chan<- client.ROMUpdate
+type StreamLogWrite chan<- []client.LogEntry
+type StreamTopoWrite chan<- client.TopoNode
+type StreamDevicesWrite chan<- []client.DeviceInfo
+type StreamUPnPWrite chan<- client.UPnP
+type StreamTimeWrite chan<- client.SystemTime
+type StreamVPNWrite chan<- client.VPN
+type StreamIPv6Write chan<- client.IPv6Info
 
 // Controller is a drawable and resizable UI interface.
 type Controller interface {
@@ -24,3 +44,9 @@ type Controller interface {
 	// Init initialises controller.
 	Init(ctx context.Context)
 }
+
+// Handler is an interactive UI controller which handles user input.
+type Handler interface {
+	// Handle processes input event and reports whether it was consumed.
+	Handle(e ui.Event) bool
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+)
+
+// UPnPSwitcher turns UPnP service on or off.
+type UPnPSwitcher interface {
+	SetUPnP(enabled bool) error
+}
+
+// NewUPnPController creates and returns UPnP mappings UI controller.
+func NewUPnPController(
+	streamStat StreamStatRead,
+	streamDevices StreamDevicesRead,
+	streamUPnP StreamUPnPRead,
+	switcher UPnPSwitcher,
+) *upnpController {
+	return &upnpController{
+		Grid:          ui.NewGrid(),
+		bodyTable:     newSelectTable(),
+		footText:      widgets.NewParagraph(),
+		streamStat:    streamStat,
+		streamDevices: streamDevices,
+		streamUPnP:    streamUPnP,
+		switcher:      switcher,
+		results:       make(chan upnpResult, 1),
+	}
+}
+
+type upnpController struct {
+	*ui.Grid
+
+	bodyTable *selectTable
+	footText  *widgets.Paragraph
+
+	streamStat    StreamStatRead
+	streamDevices StreamDevicesRead
+	streamUPnP    StreamUPnPRead
+	switcher      UPnPSwitcher
+
+	// results are outcomes of UPnP switching, they are applied by
+	// subscribe like other updates
+	results chan upnpResult
+
+	stat    client.Stat
+	devices []client.DeviceInfo
+	upnp    client.UPnP
+	status  string
+	confirm bool
+
+	once sync.Once
+}
+
+// upnpResult is an outcome of switching UPnP to the enabled state.
+type upnpResult struct {
+	enabled bool
+	err     error
+}
+
+func (c *upnpController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+}
+
+func (c *upnpController) Init(ctx context.Context) {
+	c.initUI()
+	go c.subscribe(ctx)
+}
+
+func (c *upnpController) initUI() {
+	c.bodyTable.Title = "UPnP mappings"
+	c.bodyTable.Rows = [][]string{
+		{"Protocol", "External port", "Internal IP", "Internal port", "Description", "Device"},
+	}
+
+	c.footText.Border = false
+	c.update()
+
+	c.Grid.Set(
+		ui.NewRow(.8, c.bodyTable),
+		ui.NewRow(.2, c.footText),
+	)
+}
+
+func (c *upnpController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.confirm {
+		c.confirm = false
+		c.status = ""
+		if e.ID == "y" {
+			enabled := !c.upnp.Enabled()
+			c.status = "Switching UPnP..."
+			go c.switchUPnP(enabled)
+		}
+		c.update()
+		return true
+	}
+
+	if c.bodyTable.Handle(e) {
+		return true
+	}
+	if e.ID != "u" {
+		return false
+	}
+
+	state := "on"
+	if c.upnp.Enabled() {
+		state = "off"
+	}
+	c.confirm = true
+	c.status = fmt.Sprintf("Switch UPnP %s? [y/N]", state)
+	c.update()
+	return true
+}
+
+func (c *upnpController) switchUPnP(enabled bool) {
+	c.results <- upnpResult{
+		enabled: enabled,
+		err:     c.switcher.SetUPnP(enabled),
+	}
+}
+
+func (c *upnpController) update() {
+	c.bodyTable.Rows = c.bodyTable.Rows[:1]
+	for _, m := range c.upnp.Mappings {
+		c.bodyTable.Rows = append(c.bodyTable.Rows, []string{
+			m.Protocol,
+			strconv.Itoa(m.ExternalPort),
+			m.InternalIP,
+			strconv.Itoa(m.InternalPort),
+			m.Description,
+			c.deviceName(m.InternalIP),
+		})
+	}
+
+	state := "[off](fg:red)"
+	if c.upnp.Enabled() {
+		state = "[on](fg:green)"
+	}
+
+	c.footText.Text = fmt.Sprintf(
+		"UPnP: %s | Mappings: %d | [u] switch UPnP on/off\n%s",
+		state, len(c.upnp.Mappings), c.status,
+	)
+}
+
+// apply updates UPnP state with the switching outcome.
+func (c *upnpController) apply(r upnpResult) {
+	if r.err != nil {
+		c.status = fmt.Sprintf("Failed to switch UPnP: %v", r.err)
+		return
+	}
+	c.upnp.Status = 0
+	if r.enabled {
+		c.upnp.Status = 1
+	}
+	c.status = "UPnP is switched, mappings are updated on the next fetch"
+}
+
+// deviceName returns name of the device which owns IP address,
+// device is found in the full device list and named from status data.
+func (c *upnpController) deviceName(ip string) string {
+	for _, info := range c.devices {
+		for _, addr := range info.IP {
+			if addr.IP != ip {
+				continue
+			}
+			for _, device := range c.stat.Devices {
+				if strings.EqualFold(device.Mac, info.Mac) {
+					return device.Name
+				}
+			}
+			return info.Name
+		}
+	}
+	return "-"
+}
+
+func (c *upnpController) subscribe(ctx context.Context) {
+	c.once.Do(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-c.streamStat:
+				c.Lock()
+				c.stat = s
+				c.update()
+				c.Unlock()
+			case d := <-c.streamDevices:
+				c.Lock()
+				c.devices = d
+				c.update()
+				c.Unlock()
+			case u := <-c.streamUPnP:
+				c.Lock()
+				c.upnp = u
+				c.update()
+				c.Unlock()
+			case r := <-c.results:
+				c.Lock()
+				c.apply(r)
+				c.update()
+				c.Unlock()
+			}
+		}
+	})
+}
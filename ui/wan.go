@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"miwifi-termui/client"
+)
+
+// wanChangeTTL is how long changed WAN details stay highlighted.
+const wanChangeTTL = time.Minute
+
+// wanTracker keeps last WAN details and highlights recently changed fields.
+type wanTracker struct {
+	info    client.WANInfo
+	changed map[string]time.Time
+}
+
+// update stores new WAN details and remembers which fields are changed.
+func (t *wanTracker) update(info client.WANInfo) {
+	if t.changed == nil {
+		t.changed = make(map[string]time.Time)
+	}
+
+	now := time.Now()
+	old := t.info
+	for field, values := range map[string][2]string{
+		"ip":      {old.IP, info.IP},
+		"gateway": {old.Gateway, info.Gateway},
+		"dns":     {strings.Join(old.DNS, ", "), strings.Join(info.DNS, ", ")},
+		"type":    {old.Type, info.Type},
+		"state":   {wanState(old), wanState(info)},
+	} {
+		// first received details are not a change
+		if old.Type != "" && values[0] != values[1] {
+			t.changed[field] = now
+		}
+	}
+
+	t.info = info
+}
+
+// field returns value styled as highlighted if field was recently changed.
+func (t *wanTracker) field(name, value string) string {
+	if value == "" {
+		value = "-"
+	}
+	if changedAt, ok := t.changed[name]; ok && time.Since(changedAt) < wanChangeTTL {
+		return fmt.Sprintf("[%s](fg:black,bg:yellow)", value)
+	}
+	return value
+}
+
+// summary returns one line WAN details description.
+func (t *wanTracker) summary() string {
+	return fmt.Sprintf(
+		"WAN: %s %s | Public IP: %s | Gateway: %s | DNS: %s | Uptime: %s",
+		t.field("type", strings.ToUpper(t.info.Type)),
+		t.field("state", wanState(t.info)),
+		t.field("ip", t.info.IP),
+		t.field("gateway", t.info.Gateway),
+		t.field("dns", strings.Join(t.info.DNS, ", ")),
+		t.info.UpTime.Truncate(time.Second),
+	)
+}
+
+func wanState(info client.WANInfo) string {
+	switch {
+	case !info.Link:
+		return "no link"
+	case !info.Up:
+		return "down"
+	default:
+		return "up"
+	}
+}
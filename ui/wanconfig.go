@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"miwifi-termui/client"
+	"miwifi-termui/humanize"
+)
+
+const (
+	// wanWatchTimeout is how long WAN connection is watched after
+	// configuration change before it's reported as failed.
+	wanWatchTimeout = 2 * time.Minute
+	// wanWatchGrace skips status fetched before router applied changes.
+	wanWatchGrace = 5 * time.Second
+)
+
+// WANConfigurator reads and changes WAN connection configuration.
+type WANConfigurator interface {
+	WANConfig() (client.WANConfig, error)
+	SetWANConfig(cfg client.WANConfig) error
+	CloneMac(mac string) error
+}
+
+// NewWANConfigController creates and returns WAN configuration UI controller.
+func NewWANConfigController(
+	streamStat StreamStatRead,
+	streamWAN StreamWANRead,
+	configurator WANConfigurator,
+) *wanConfigController {
+	return &wanConfigController{
+		Grid:     ui.NewGrid(),
+		bodyText: widgets.NewParagraph(),
+		footText: widgets.NewParagraph(),
+		wanForm: newForm("WAN configuration",
+			"Mode (dhcp/pppoe/static)", "PPPoE username", "PPPoE password",
+			"IP", "Netmask", "Gateway", "DNS (comma separated, empty for auto)", "MTU (0 for default)", "MAC",
+		).Secret(2),
+		streamStat:   streamStat,
+		streamWAN:    streamWAN,
+		configurator: configurator,
+	}
+}
+
+type wanConfigController struct {
+	*ui.Grid
+
+	bodyText *widgets.Paragraph
+	footText *widgets.Paragraph
+	wanForm  *form
+
+	streamStat   StreamStatRead
+	streamWAN    StreamWANRead
+	configurator WANConfigurator
+
+	cfg       client.WANConfig
+	stat      client.Stat
+	wan       wanTracker
+	appliedAt time.Time
+	status    string
+
+	once sync.Once
+}
+
+func (c *wanConfigController) Resize() {
+	w, h := ui.TerminalDimensions()
+	c.Grid.SetRect(0, 0, w, h)
+	c.wanForm.Resize(c.GetRect())
+}
+
+func (c *wanConfigController) Draw(buf *ui.Buffer) {
+	c.Grid.Draw(buf)
+	c.wanForm.Draw(buf)
+}
+
+func (c *wanConfigController) Init(ctx context.Context) {
+	c.initUI()
+	go c.refresh()
+	go c.subscribe(ctx)
+}
+
+func (c *wanConfigController) initUI() {
+	c.bodyText.Title = "WAN configuration"
+	c.bodyText.PaddingLeft = 1
+
+	c.footText.Border = false
+	c.status = "Loading configuration..."
+	c.update()
+
+	c.Grid.Set(
+		ui.NewRow(.8, c.bodyText),
+		ui.NewRow(.2, c.footText),
+	)
+}
+
+func (c *wanConfigController) Handle(e ui.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.wanForm.Handle(e) {
+		return true
+	}
+
+	switch e.ID {
+	case "e":
+		mtu := ""
+		if c.cfg.MTU > 0 {
+			mtu = strconv.Itoa(c.cfg.MTU)
+		}
+		c.wanForm.Open(c.save,
+			c.cfg.Mode, c.cfg.Username, "",
+			c.cfg.IP, c.cfg.Mask, c.cfg.Gateway, strings.Join(c.cfg.DNS, ","), mtu, c.cfg.Mac,
+		)
+	case "r":
+		go c.refresh()
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *wanConfigController) save(values []string) {
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	cfg := client.WANConfig{
+		Mode:     strings.ToLower(values[0]),
+		Username: values[1],
+		Password: values[2],
+		IP:       values[3],
+		Mask:     values[4],
+		Gateway:  values[5],
+		Mac:      values[8],
+	}
+	for _, dns := range strings.Split(values[6], ",") {
+		if dns = strings.TrimSpace(dns); dns != "" {
+			cfg.DNS = append(cfg.DNS, dns)
+		}
+	}
+	if values[7] != "" {
+		mtu, err := strconv.Atoi(values[7])
+		if err != nil {
+			c.status = fmt.Sprintf("Invalid MTU: %q", values[7])
+			c.update()
+			return
+		}
+		cfg.MTU = mtu
+	}
+
+	if err := cfg.Validate(); err != nil {
+		c.status = err.Error()
+		c.update()
+		return
+	}
+
+	c.status = "Applying configuration..."
+	c.update()
+	go c.apply(cfg, c.cfg.Mac)
+}
+
+// apply saves configuration, currentMac is a MAC address before the change.
+func (c *wanConfigController) apply(cfg client.WANConfig, currentMac string) {
+	if err := c.configurator.SetWANConfig(cfg); err != nil {
+		c.setStatus(fmt.Sprintf("Failed: %v", err))
+		return
+	}
+	if cfg.Mac != "" && !strings.EqualFold(cfg.Mac, currentMac) {
+		if err := c.configurator.CloneMac(cfg.Mac); err != nil {
+			c.setStatus(fmt.Sprintf("Configuration is saved, failed to clone MAC: %v", err))
+			return
+		}
+	}
+
+	c.Lock()
+	c.appliedAt = time.Now()
+	c.status = "Configuration is saved, waiting for WAN connection..."
+	c.update()
+	c.Unlock()
+
+	c.refresh()
+}
+
+func (c *wanConfigController) setStatus(status string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.status = status
+	c.update()
+}
+
+// watch reports whether WAN connection came up after configuration change,
+// connection is up when router reports it and WAN traffic flows.
+func (c *wanConfigController) watch() {
+	if c.appliedAt.IsZero() {
+		return
+	}
+
+	elapsed := time.Since(c.appliedAt)
+	switch {
+	case elapsed < wanWatchGrace:
+		return
+	case c.wan.info.Up && (c.stat.WAN.DownSpeed > 0 || c.stat.WAN.UpSpeed > 0):
+		c.status = fmt.Sprintf(
+			"[WAN connection is up in %s, public IP %s](fg:green)",
+			elapsed.Truncate(time.Second), c.wan.info.IP,
+		)
+	case elapsed > wanWatchTimeout:
+		c.status = fmt.Sprintf(
+			"[WAN connection didn't come up in %s, state: %s](fg:red)",
+			wanWatchTimeout, wanState(c.wan.info),
+		)
+	default:
+		return
+	}
+	c.appliedAt = time.Time{}
+}
+
+func (c *wanConfigController) refresh() {
+	cfg, err := c.configurator.WANConfig()
+	if err != nil {
+		c.setStatus(fmt.Sprintf("Failed to load configuration: %v", err))
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.cfg = cfg
+
+	if c.status == "Loading configuration..." {
+		c.status = ""
+	}
+	c.update()
+}
+
+func (c *wanConfigController) update() {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "Mode:      %s\n", strings.ToUpper(c.cfg.Mode))
+	switch c.cfg.Mode {
+	case client.WANModePPPoE:
+		fmt.Fprintf(&text, "Username:  %s\n", c.cfg.Username)
+	case client.WANModeStatic:
+		fmt.Fprintf(&text, "IP:        %s/%s\nGateway:   %s\n", c.cfg.IP, c.cfg.Mask, c.cfg.Gateway)
+	}
+
+	dns := "auto"
+	if len(c.cfg.DNS) > 0 {
+		dns = strings.Join(c.cfg.DNS, ", ")
+	}
+	mtu := "default"
+	if c.cfg.MTU > 0 {
+		mtu = strconv.Itoa(c.cfg.MTU)
+	}
+	fmt.Fprintf(&text, "DNS:       %s\nMTU:       %s\nMAC:       %s\n\n", dns, mtu, c.cfg.Mac)
+
+	text.WriteString(c.wan.summary())
+	fmt.Fprintf(&text, "\nSpeed: ↓%s/s ↑%s/s",
+		humanize.Bytes(c.stat.WAN.DownSpeed),
+		humanize.Bytes(c.stat.WAN.UpSpeed),
+	)
+	c.bodyText.Text = text.String()
+
+	c.footText.Text = fmt.Sprintf("[e] edit  [r] reload\n%s", c.status)
+}
+
+func (c *wanConfigController) subscribe(ctx context.Context) {
+	c.once.Do(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-c.streamStat:
+				c.Lock()
+				c.stat = s
+				c.watch()
+				c.update()
+				c.Unlock()
+			case w := <-c.streamWAN:
+				c.Lock()
+				c.wan.update(w)
+				c.watch()
+				c.update()
+				c.Unlock()
+			}
+		}
+	})
+}